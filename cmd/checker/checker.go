@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
+	"demo/pkg/authorization"
+	_ "demo/pkg/pb"
 	"demo/pkg/policy"
+	"demo/pkg/protofacts"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/flynn/biscuit-go"
 	"github.com/flynn/biscuit-go/parser"
 	"github.com/flynn/biscuit-go/sig"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 type stringSliceFlag []string
@@ -28,12 +36,17 @@ func (f *stringSliceFlag) Set(value string) error {
 func main() {
 	log.SetFlags(0)
 
-	var cfg, policyName, rule string
+	var cfg, policyName, rule, msgType, msgJSON, repeatedFormat, callTime, timezone string
 	var facts stringSliceFlag
 	flag.StringVar(&cfg, "c", "", "a policy definition file")
 	flag.StringVar(&policyName, "p", "", "restrict the check to a policy name, default will check all policies")
 	flag.Var(&facts, "f", "repeatable, fact added to the verifier")
 	flag.StringVar(&rule, "r", "", "a rule to query the verifier with and print results")
+	flag.StringVar(&msgType, "m", "", "full name of the request message type, i.e., demo.api.v1.ReadRequest, converted to arg facts as the server does")
+	flag.StringVar(&msgJSON, "a", "{}", "the request message arguments, in JSON, with -m")
+	flag.StringVar(&repeatedFormat, "repeated", "set", "format of the repeated message fields, with -m, as set with the server WithRepeatedFormat option: set, indexed or set_and_indexed")
+	flag.StringVar(&callTime, "t", "", "time of the call, in RFC 3339, i.e., 2021-03-04T10:00:00Z, added as time, weekday and hour facts as the server does")
+	flag.StringVar(&timezone, "tz", "UTC", "timezone of the weekday and hour facts, with -t")
 	flag.Parse()
 
 	if cfg == "" {
@@ -60,6 +73,26 @@ func main() {
 		testedPolicies = map[string]policy.Policy{policyName: p}
 	}
 
+	var callFacts []biscuit.Fact
+	if msgType != "" {
+		format, err := parseRepeatedFormat(repeatedFormat)
+		if err != nil {
+			log.Fatalf("invalid -repeated: %v", err)
+		}
+		callFacts, err = requestFacts(msgType, msgJSON, format)
+		if err != nil {
+			log.Fatalf("failed to convert request: %v", err)
+		}
+		log.Printf("Converted %s request to %d facts", msgType, len(callFacts))
+	}
+	if callTime != "" {
+		timeFacts, err := callTimeFacts(callTime, timezone)
+		if err != nil {
+			log.Fatalf("failed to convert call time: %v", err)
+		}
+		callFacts = append(callFacts, timeFacts...)
+	}
+
 	for _, policy := range testedPolicies {
 		log.Printf("Testing policy %q", policy.Name)
 		v, err := getVerifier(policy)
@@ -69,7 +102,7 @@ func main() {
 
 		p := parser.New()
 
-		if len(facts) > 0 {
+		if len(facts) > 0 || len(callFacts) > 0 {
 			for _, f := range facts {
 				fact, err := p.Fact(f)
 				if err != nil {
@@ -77,6 +110,9 @@ func main() {
 				}
 				v.AddFact(fact)
 			}
+			for _, fact := range callFacts {
+				v.AddFact(fact)
+			}
 			if err := v.Verify(); err != nil {
 				log.Printf("- ERROR: %v", err)
 			} else {
@@ -124,3 +160,49 @@ func getVerifier(policy policy.Policy) (biscuit.Verifier, error) {
 
 	return verifier, nil
 }
+
+// requestFacts converts the JSON request of the msgType message to facts, with the default server options, and the
+// repeated message fields in format. Requests exceeding authorization.DefaultFlattenLimits are rejected, as the
+// server does.
+func requestFacts(msgType, msgJSON string, format protofacts.RepeatedFormat) ([]biscuit.Fact, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(msgType))
+	if err != nil {
+		return nil, err
+	}
+	msg := mt.New().Interface()
+	if err := protojson.Unmarshal([]byte(msgJSON), msg); err != nil {
+		return nil, err
+	}
+	return protofacts.Facts(msg, protofacts.Options{
+		RepeatedFormat: format,
+		Limits:         authorization.DefaultFlattenLimits,
+	})
+}
+
+// parseRepeatedFormat returns the protofacts.RepeatedFormat named s.
+func parseRepeatedFormat(s string) (protofacts.RepeatedFormat, error) {
+	switch s {
+	case "set":
+		return protofacts.RepeatedSet, nil
+	case "indexed":
+		return protofacts.RepeatedIndexed, nil
+	case "set_and_indexed":
+		return protofacts.RepeatedSetAndIndexed, nil
+	default:
+		return 0, fmt.Errorf("unknown repeated format %q", s)
+	}
+}
+
+// callTimeFacts returns the time facts of a call at callTime, in RFC 3339, with the weekday and hour in timezone.
+func callTimeFacts(callTime, timezone string) ([]biscuit.Fact, error) {
+	t, err := time.Parse(time.RFC3339, callTime)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	provider := authorization.TimeFactProvider(func() time.Time { return t }, loc)
+	return provider.Facts(context.Background(), "", nil)
+}
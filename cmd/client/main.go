@@ -1,12 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"demo/pkg/authorization"
 	"demo/pkg/pb"
 	"demo/pkg/policy"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -15,8 +17,10 @@ import (
 
 	"github.com/flynn/biscuit-go"
 	"github.com/flynn/biscuit-go/cookbook/signedbiscuit"
+	"github.com/flynn/biscuit-go/datalog"
 	"github.com/flynn/biscuit-go/sig"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -30,6 +34,10 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	rootKeyID, err := findRootKeyID(rootPubBytes)
+	if err != nil {
+		panic(err)
+	}
 
 	for _, role := range []string{"guest", "auditor", "developer", "admin"} {
 		baseToken, err := login(role)
@@ -37,7 +45,7 @@ func main() {
 			panic(err)
 		}
 
-		clientInterceptor, err := authorization.NewBiscuitClientInterceptor(rootPubBytes, userPrivKeyBytes, baseToken)
+		clientInterceptor, err := authorization.NewBiscuitClientInterceptor(rootPubBytes, userPrivKeyBytes, baseToken, authorization.WithRootKeyID(rootKeyID))
 		if err != nil {
 			panic(err)
 		}
@@ -72,7 +80,7 @@ func testAuthorization(clientInterceptor authorization.BiscuitClientInterceptor,
 	}
 
 	for envName, env := range pb.Env_value {
-		_, err := c.Read(ctx, &pb.ReadRequest{
+		resp, err := c.Read(ctx, &pb.ReadRequest{
 			Env:        pb.Env(env),
 			Names:      []string{"entity1", "entity2"},
 			ExpireTime: timestamppb.New(time.Now()),
@@ -82,6 +90,9 @@ func testAuthorization(clientInterceptor authorization.BiscuitClientInterceptor,
 			Entities:   []*pb.Entity{{Name: "entity1", Value: 1}, {Name: "entity2", Value: 2}, {Name: "entity3", Value: 3}},
 		})
 		printStatus(role, envName, "Read", err)
+		if err == nil {
+			fmt.Printf("[%s][%s] Read entities: %v\n", role, envName, resp.Entities)
+		}
 	}
 
 	for envName, env := range pb.Env_value {
@@ -104,20 +115,29 @@ func testAuthorization(clientInterceptor authorization.BiscuitClientInterceptor,
 
 func printStatus(role, envName, method string, err error) {
 	var auth, msg string
-	if errors.Is(err, authorization.ErrNotAuthorized) {
+	switch status.Code(err) {
+	case codes.OK:
+		auth = "ALLOWD"
+		msg = "ok"
+	case codes.PermissionDenied:
 		auth = "DENIED"
 		s, _ := status.FromError(err)
 		msg = s.Message()
-	} else if err != nil {
+	default:
 		auth = "FAILED"
 		msg = err.Error()
-	} else {
-		auth = "ALLOWD"
-		msg = "ok"
+		if reason := authorization.ErrorReason(err); reason != "" {
+			msg = fmt.Sprintf("%s (%s)", msg, reason)
+		}
 	}
 	fmt.Printf("[%s][%s][%s] %s response: %s\n", role, envName, auth, method, msg)
 }
 
+// grantLifetimes are the lifetimes of the short-lived role grants, expiring with a caveat set at login
+var grantLifetimes = map[string]time.Duration{
+	"break_glass": time.Hour,
+}
+
 // login simulate an authorization server returning a biscuit
 func login(role string) (string, error) {
 	rootPrivBytes, err := ioutil.ReadFile("./root.private.demo.key")
@@ -130,6 +150,11 @@ func login(role string) (string, error) {
 	}
 	root := sig.NewKeypair(sk)
 
+	rootKeyID, err := findRootKeyID(root.Public().Bytes())
+	if err != nil {
+		return "", err
+	}
+
 	userPubKey, err := ioutil.ReadFile("./user.public.demo.key")
 	if err != nil {
 		return "", err
@@ -170,6 +195,14 @@ func login(role string) (string, error) {
 		return "", err
 	}
 
+	// let the server know which root key signed the token
+	if err := builder.AddAuthorityFact(biscuit.Fact{Predicate: biscuit.Predicate{
+		Name: authorization.RootKeyIDFact,
+		IDs:  []biscuit.Atom{biscuit.Symbol("authority"), biscuit.String(rootKeyID)},
+	}}); err != nil {
+		return "", err
+	}
+
 	for _, r := range rolePolicy.Rules {
 		if err := builder.AddAuthorityRule(r); err != nil {
 			return "", nil
@@ -180,6 +213,11 @@ func login(role string) (string, error) {
 			return "", nil
 		}
 	}
+	if lifetime, ok := grantLifetimes[role]; ok {
+		if err := builder.AddAuthorityCaveat(expiryCaveat(time.Now().Add(lifetime))); err != nil {
+			return "", err
+		}
+	}
 
 	bisc, err := builder.Build()
 	if err != nil {
@@ -193,3 +231,37 @@ func login(role string) (string, error) {
 
 	return base64.URLEncoding.EncodeToString(ser), nil
 }
+
+// expiryCaveat returns a caveat failing once expireTime passed, on servers adding the time facts:
+// *not_expired($0) <- time(#ambient, $0) @ $0 <= expireTime
+func expiryCaveat(expireTime time.Time) biscuit.Caveat {
+	return biscuit.Caveat{Queries: []biscuit.Rule{{
+		Head: biscuit.Predicate{Name: "not_expired", IDs: []biscuit.Atom{biscuit.Variable("0")}},
+		Body: []biscuit.Predicate{{Name: "time", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.Variable("0")}}},
+		Constraints: []biscuit.Constraint{{
+			Name: biscuit.Variable("0"),
+			Checker: biscuit.DateComparisonChecker{
+				Comparison: datalog.DateComparisonBefore,
+				Date:       biscuit.Date(expireTime),
+			},
+		}},
+	}}}
+}
+
+// findRootKeyID returns the ID of the root public key in the keyring written by cmd/keys
+func findRootKeyID(rootPubKey []byte) (string, error) {
+	keyringBytes, err := ioutil.ReadFile("./root.keyring.demo.json")
+	if err != nil {
+		return "", err
+	}
+	var entries []authorization.RootKeyringEntry
+	if err := json.Unmarshal(keyringBytes, &entries); err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if bytes.Equal(e.PublicKey, rootPubKey) {
+			return e.ID, nil
+		}
+	}
+	return "", errors.New("root public key not found in keyring")
+}
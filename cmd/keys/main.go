@@ -5,17 +5,30 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
+	"demo/pkg/authorization"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
 	"io/ioutil"
+	"time"
 
 	"github.com/flynn/biscuit-go/sig"
 )
 
 func main() {
+	rotate := flag.Bool("rotate", false, "only generate a new root key pair, and add it to the existing root keyring")
+	grace := flag.Duration("grace", 0, "on rotation, expire the previous root keys after this duration, or never when 0")
+	flag.Parse()
+
 	privateKeyPath := "./root.private.demo.key"
 	publicKeyPath := "./root.public.demo.key"
-	if err := generateRS255KeyPair(privateKeyPath, publicKeyPath); err != nil {
+	keyringPath := "./root.keyring.demo.json"
+	if err := generateRootKey(privateKeyPath, publicKeyPath, keyringPath, *rotate, *grace); err != nil {
 		panic(err)
 	}
+	if *rotate {
+		return
+	}
 
 	audiencePrivateKeyPath := "./audience.private.demo.key"
 	audiencePublicKeyPath := "./audience.public.demo.key"
@@ -30,7 +43,30 @@ func main() {
 	}
 }
 
-func generateRS255KeyPair(privateKeyPath, publicKeyPath string) error {
+// generateRootKey generates a new root key pair, and adds its public key to the keyring. On rotation, the previous
+// keys are kept in the keyring so the tokens they signed remain valid, until grace when not 0.
+// Otherwise, the keyring only holds the new key.
+func generateRootKey(privateKeyPath, publicKeyPath, keyringPath string, rotate bool, grace time.Duration) error {
+	now := time.Now().UTC()
+
+	var entries []authorization.RootKeyringEntry
+	if rotate {
+		keyringBytes, err := ioutil.ReadFile(keyringPath)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(keyringBytes, &entries); err != nil {
+			return err
+		}
+		if grace > 0 {
+			for i := range entries {
+				if entries[i].NotAfter.IsZero() {
+					entries[i].NotAfter = now.Add(grace)
+				}
+			}
+		}
+	}
+
 	kp := sig.GenerateKeypair(rand.Reader)
 	if err := ioutil.WriteFile(privateKeyPath, kp.Private().Bytes(), 0600); err != nil {
 		return err
@@ -38,7 +74,22 @@ func generateRS255KeyPair(privateKeyPath, publicKeyPath string) error {
 	if err := ioutil.WriteFile(publicKeyPath, kp.Public().Bytes(), 0644); err != nil {
 		return err
 	}
-	return nil
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	entries = append(entries, authorization.RootKeyringEntry{
+		ID:        hex.EncodeToString(id),
+		PublicKey: kp.Public().Bytes(),
+		NotBefore: now,
+	})
+
+	keyringBytes, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(keyringPath, keyringBytes, 0644)
 }
 
 func generateECDSAKeyPair(privateKeyPath, publicKeyPath string) error {
@@ -5,18 +5,23 @@ import (
 	"demo/pkg/antireplay"
 	"demo/pkg/authorization"
 	"demo/pkg/pb"
+	"demo/pkg/protofacts"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net"
 	"time"
 
+	"github.com/flynn/biscuit-go"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
 
 type demoServer struct {
 	pb.UnimplementedDemoServer
+
+	// owners maps entity names to their owner user ID
+	owners map[string]string
 }
 
 var _ pb.DemoServer = (*demoServer)(nil)
@@ -29,15 +34,37 @@ func (d *demoServer) Create(_ context.Context, _ *pb.CreateRequest) (*pb.Respons
 	return &pb.Response{Status: pb.Response_OK}, nil
 }
 
-func (d *demoServer) Read(_ context.Context, _ *pb.ReadRequest) (*pb.Response, error) {
-	return &pb.Response{Status: pb.Response_OK}, nil
+func (d *demoServer) Read(_ context.Context, req *pb.ReadRequest) (*pb.Response, error) {
+	entities := make([]*pb.Entity, 0, len(req.Names))
+	for i, name := range req.Names {
+		entities = append(entities, &pb.Entity{Name: name, Value: int64(i + 1)})
+	}
+	return &pb.Response{Status: pb.Response_OK, Entities: entities}, nil
 }
 
 func (d *demoServer) Update(_ context.Context, _ *pb.UpdateRequest) (*pb.Response, error) {
 	return &pb.Response{Status: pb.Response_OK}, nil
 }
 
-func (d *demoServer) Delete(_ context.Context, _ *pb.DeleteRequest) (*pb.Response, error) {
+func (d *demoServer) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.Response, error) {
+	caller, ok := authorization.CallerFromContext(ctx)
+	if !ok {
+		return nil, authorization.ErrMissingToken
+	}
+
+	// only the entity owner can delete it
+	ownerFact := biscuit.Fact{Predicate: biscuit.Predicate{
+		Name: "owner",
+		IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(req.Name), biscuit.String(d.owners[req.Name])},
+	}}
+	callerFact := biscuit.Fact{Predicate: biscuit.Predicate{
+		Name: "caller",
+		IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(caller.UserID)},
+	}}
+	if err := authorization.Check(ctx, "entity_owner", ownerFact, callerFact); err != nil {
+		return nil, err
+	}
+
 	return &pb.Response{Status: pb.Response_OK}, nil
 }
 
@@ -47,28 +74,58 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	rootPubKey, err := ioutil.ReadFile("./root.public.demo.key")
+	rootKeyringBytes, err := ioutil.ReadFile("./root.keyring.demo.json")
+	if err != nil {
+		panic(err)
+	}
+	rootKeyring, err := authorization.ParseRootKeyring(rootKeyringBytes)
 	if err != nil {
 		panic(err)
 	}
 
+	audiencePubKeyBytes, err := ioutil.ReadFile("./audience.public.demo.key")
+	if err != nil {
+		panic(err)
+	}
+	audiencePubKey, err := authorization.ParseAudiencePublicKey(audiencePubKeyBytes)
+	if err != nil {
+		panic(err)
+	}
 	logger, err := zap.NewDevelopment()
 	if err != nil {
 		panic(err)
 	}
 
-	antiReplay := antireplay.NewChecker(antireplay.NewRAMStore(), 5*time.Second, 60*time.Minute)
-	i, err := authorization.NewBiscuitServerInterceptor(rootPubKey, antiReplay, logger.Named("biscuit-interceptor"))
+	nonceStore := antireplay.NewRAMStore(antireplay.WithTTL(60 * time.Minute))
+	defer nonceStore.Stop()
+	antiReplay := antireplay.NewChecker(nonceStore, 5*time.Second, 60*time.Minute)
+	i, err := authorization.NewBiscuitServerInterceptor(nil,
+		authorization.WithRootKeyring(rootKeyring),
+		authorization.WithAudience("http://audience.local", audiencePubKey),
+		authorization.WithAntiReplay(antiReplay),
+		authorization.WithVerifierPolicyFile("./demo-v1-Demo.verifier.policy"),
+		authorization.WithAuthorizationCache(authorization.CacheConfig{
+			Size: 1024,
+			TTL:  time.Minute,
+		}),
+		authorization.WithRepeatedFormat(protofacts.RepeatedSetAndIndexed),
+		// business hours are in the server local time
+		authorization.WithTimeFacts(time.Local),
+		authorization.WithMetadataFacts(authorization.MetadataFactsConfig{
+			Keys: []string{"x-tenant-id", "x-request-reason"},
+		}),
+		authorization.WithResponseAuthorization(),
+		authorization.WithLogger(logger.Named("biscuit-interceptor")),
+	)
 	if err != nil {
 		panic(err)
 	}
 
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(i.Unary),
-		grpc.StreamInterceptor(i.Stream),
-	)
+	grpcServer := grpc.NewServer(authorization.ServerOptions(i)...)
 
-	pb.RegisterDemoServer(grpcServer, &demoServer{})
+	pb.RegisterDemoServer(grpcServer, &demoServer{
+		owners: map[string]string{"entity1": "userID"},
+	})
 
 	fmt.Println("server listening on localhost:8888")
 	if err := grpcServer.Serve(lis); err != nil {
@@ -1,7 +1,6 @@
 package antireplay
 
 import (
-	"bytes"
 	"errors"
 	"time"
 )
@@ -40,20 +39,5 @@ func (c *checker) Check(nonce Nonce) error {
 		return ErrNonceOOB
 	}
 
-	existingNonces, err := c.store.Get(nonce.ID)
-	if err != nil {
-		return err
-	}
-
-	for _, n := range existingNonces {
-		// skip too old nonces
-		if n.CreatedAt.Before(now.Add(-c.nonceMaxAge)) {
-			continue
-		}
-		if bytes.Equal(n.Value, nonce.Value) {
-			return ErrReplay
-		}
-	}
-
-	return c.store.Insert(nonce)
+	return c.store.InsertIfAbsent(nonce, c.nonceMaxAge)
 }
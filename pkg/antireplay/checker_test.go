@@ -1,6 +1,8 @@
 package antireplay
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -125,3 +127,58 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckConcurrentReplays(t *testing.T) {
+	stores := map[string]Store{
+		"ram":    NewRAMStore(),
+		"legacy": NewLegacyStoreAdapter(&sliceStore{}),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			checker := NewChecker(store, time.Second, time.Minute)
+			nonce := Nonce{ID: "id1", CreatedAt: time.Now(), Value: []byte{1}}
+
+			const attempts = 32
+			var wg sync.WaitGroup
+			var accepted, replays int32
+			for i := 0; i < attempts; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					switch err := checker.Check(nonce); err {
+					case nil:
+						atomic.AddInt32(&accepted, 1)
+					case ErrReplay:
+						atomic.AddInt32(&replays, 1)
+					default:
+						t.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			require.Equal(t, int32(1), accepted, "a single check accepts the nonce")
+			require.Equal(t, int32(attempts-1), replays)
+		})
+	}
+}
+
+// sliceStore is a LegacyStore, unsafe for concurrent use.
+type sliceStore struct {
+	nonces []Nonce
+}
+
+func (s *sliceStore) Insert(nonce Nonce) error {
+	s.nonces = append(s.nonces, nonce)
+	return nil
+}
+
+func (s *sliceStore) Get(id string) ([]Nonce, error) {
+	var nonces []Nonce
+	for _, n := range s.nonces {
+		if n.ID == id {
+			nonces = append(nonces, n)
+		}
+	}
+	return nonces, nil
+}
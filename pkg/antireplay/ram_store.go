@@ -0,0 +1,337 @@
+package antireplay
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultTTL is the default duration nonces are kept by a RAMStore, from their creation time.
+	DefaultTTL = 60 * time.Minute
+	// DefaultShards is the default number of shards of a RAMStore.
+	DefaultShards = 64
+	// DefaultMaxNonces is the default maximum number of nonces held by a RAMStore.
+	DefaultMaxNonces = 1000000
+	// DefaultJanitorInterval is the default interval between two removals of the expired nonces of a RAMStore.
+	DefaultJanitorInterval = time.Minute
+)
+
+// ErrStoreFull is returned when inserting a nonce in a RAMStore already holding its maximum number of nonces, once
+// the expired nonces of the ID shard, and with InsertIfAbsent the nonces of the ID older than its maxAge, are removed.
+// Checks then fail until some nonces expire, rather than forgetting nonces which could be replayed.
+var ErrStoreFull = errors.New("authorization: nonce store is full")
+
+// RAMStoreOption configures the store created by NewRAMStore. Non positive values keep the defaults.
+type RAMStoreOption func(*ramStoreConfig)
+
+type ramStoreConfig struct {
+	ttl             time.Duration
+	shards          int
+	maxNonces       int
+	janitorInterval time.Duration
+	now             func() time.Time
+}
+
+// WithTTL sets how long nonces are kept from their creation time, which should be at least the nonce max age
+// of the checker using the store. Defaults to DefaultTTL.
+func WithTTL(ttl time.Duration) RAMStoreOption {
+	return func(c *ramStoreConfig) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithShards sets the number of shards, each holding the nonces of a part of the IDs behind its own lock.
+// Defaults to DefaultShards.
+func WithShards(shards int) RAMStoreOption {
+	return func(c *ramStoreConfig) {
+		if shards > 0 {
+			c.shards = shards
+		}
+	}
+}
+
+// WithMaxNonces bounds the number of nonces held by the store, whatever their shards, see ErrStoreFull.
+// Defaults to DefaultMaxNonces.
+func WithMaxNonces(maxNonces int) RAMStoreOption {
+	return func(c *ramStoreConfig) {
+		if maxNonces > 0 {
+			c.maxNonces = maxNonces
+		}
+	}
+}
+
+// WithJanitorInterval sets the interval between two removals of the expired nonces. Defaults to DefaultJanitorInterval.
+func WithJanitorInterval(interval time.Duration) RAMStoreOption {
+	return func(c *ramStoreConfig) {
+		if interval > 0 {
+			c.janitorInterval = interval
+		}
+	}
+}
+
+// WithClock sets the function returning the current time. Defaults to time.Now.
+func WithClock(now func() time.Time) RAMStoreOption {
+	return func(c *ramStoreConfig) {
+		if now != nil {
+			c.now = now
+		}
+	}
+}
+
+// RAMStore is an in memory Store, safe for concurrent use. Nonces are held in shards by ID, and expire once
+// their TTL elapsed since their creation time. A background janitor, started by the first insertion, removes
+// the expired nonces until Stop is called.
+type RAMStore struct {
+	// count is the number of nonces held by all the shards, first in the struct for 64-bit atomic alignment
+	count     int64
+	maxNonces int64
+	shards    []ramShard
+	ttl       time.Duration
+	now       func() time.Time
+
+	janitorInterval time.Duration
+	janitorStarted  int32
+	janitorMu       sync.Mutex
+	started         bool
+	stopped         bool
+	stop            chan struct{}
+	done            chan struct{}
+}
+
+var _ Store = (*RAMStore)(nil)
+
+// ramShard holds the nonces of a part of the IDs.
+type ramShard struct {
+	mu     sync.Mutex
+	nonces map[string][]Nonce
+}
+
+// NewRAMStore creates an in memory store. Stop must be called once the store isn't used anymore.
+func NewRAMStore(opts ...RAMStoreOption) *RAMStore {
+	cfg := &ramStoreConfig{
+		ttl:             DefaultTTL,
+		shards:          DefaultShards,
+		maxNonces:       DefaultMaxNonces,
+		janitorInterval: DefaultJanitorInterval,
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	shards := make([]ramShard, cfg.shards)
+	for i := range shards {
+		shards[i].nonces = make(map[string][]Nonce)
+	}
+
+	return &RAMStore{
+		maxNonces:       int64(cfg.maxNonces),
+		shards:          shards,
+		ttl:             cfg.ttl,
+		now:             cfg.now,
+		janitorInterval: cfg.janitorInterval,
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+// Insert stores nonce, or returns ErrStoreFull when the store is full.
+func (s *RAMStore) Insert(nonce Nonce) error {
+	shard, now := s.lockShard(nonce.ID)
+	defer shard.mu.Unlock()
+	return s.insertLocked(shard, nonce, now, time.Time{})
+}
+
+// InsertIfAbsent stores nonce, unless its shard holds an unexpired nonce with the same ID and value created less
+// than maxAge ago, returning ErrReplay, or the store is full, returning ErrStoreFull. The nonces of the ID created
+// before maxAge can't be replayed anymore, and are removed when the store is full, so a single ID can't keep the
+// store full with nonces which are never checked.
+func (s *RAMStore) InsertIfAbsent(nonce Nonce, maxAge time.Duration) error {
+	shard, now := s.lockShard(nonce.ID)
+	defer shard.mu.Unlock()
+
+	// expired nonces are forgotten, even when the store TTL is shorter than maxAge
+	notBefore := now.Add(-maxAge)
+	if ttlStart := now.Add(-s.ttl); notBefore.Before(ttlStart) {
+		notBefore = ttlStart.Add(time.Nanosecond)
+	}
+	if containsNonce(shard.nonces[nonce.ID], nonce, notBefore) {
+		return ErrReplay
+	}
+	return s.insertLocked(shard, nonce, now, notBefore)
+}
+
+// lockShard starts the janitor when needed, and returns the locked shard of id, with the current time.
+func (s *RAMStore) lockShard(id string) (*ramShard, time.Time) {
+	if atomic.LoadInt32(&s.janitorStarted) == 0 {
+		s.startJanitor()
+	}
+
+	now := s.now()
+	shard := s.shard(id)
+	shard.mu.Lock()
+	return shard, now
+}
+
+// insertLocked stores nonce in the locked shard, or returns ErrStoreFull when the store is full. A full store
+// first removes the expired nonces of the shard, and the nonces of the ID created before notBefore, unless zero.
+func (s *RAMStore) insertLocked(shard *ramShard, nonce Nonce, now, notBefore time.Time) error {
+	if !s.reserve() {
+		removed := shard.removeExpired(now, s.ttl)
+		if !notBefore.IsZero() {
+			removed += shard.removeBefore(nonce.ID, notBefore)
+		}
+		atomic.AddInt64(&s.count, -int64(removed))
+		if !s.reserve() {
+			return ErrStoreFull
+		}
+	}
+
+	shard.nonces[nonce.ID] = append(shard.nonces[nonce.ID], nonce)
+	return nil
+}
+
+// reserve counts a new nonce, unless the store already holds its maximum number of nonces.
+func (s *RAMStore) reserve() bool {
+	if atomic.AddInt64(&s.count, 1) <= s.maxNonces {
+		return true
+	}
+	atomic.AddInt64(&s.count, -1)
+	return false
+}
+
+// Get returns the unexpired nonces of id.
+func (s *RAMStore) Get(id string) ([]Nonce, error) {
+	now := s.now()
+	shard := s.shard(id)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	return s.unexpired(shard.nonces[id], now), nil
+}
+
+// unexpired returns a copy of the nonces which didn't expire at now.
+func (s *RAMStore) unexpired(nonces []Nonce, now time.Time) []Nonce {
+	var kept []Nonce
+	for _, n := range nonces {
+		if !expired(n, now, s.ttl) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// Len returns the number of nonces held by the store, including the expired ones not removed yet.
+func (s *RAMStore) Len() int {
+	return int(atomic.LoadInt64(&s.count))
+}
+
+// RemoveExpired removes the expired nonces, as the janitor does on each interval.
+func (s *RAMStore) RemoveExpired() {
+	now := s.now()
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mu.Lock()
+		removed := shard.removeExpired(now, s.ttl)
+		shard.mu.Unlock()
+		atomic.AddInt64(&s.count, -int64(removed))
+	}
+}
+
+// Stop stops the janitor, and waits for it to return. The store can still be used, without removing the expired
+// nonces but on insertions into a full store. Stop can be called several times.
+func (s *RAMStore) Stop() {
+	s.janitorMu.Lock()
+	defer s.janitorMu.Unlock()
+	if s.stopped {
+		return
+	}
+
+	s.stopped = true
+	close(s.stop)
+	if s.started {
+		<-s.done
+	}
+}
+
+// startJanitor starts the janitor, unless it already started or the store is stopped.
+func (s *RAMStore) startJanitor() {
+	s.janitorMu.Lock()
+	defer s.janitorMu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+
+	s.started = true
+	atomic.StoreInt32(&s.janitorStarted, 1)
+	go s.janitor()
+}
+
+func (s *RAMStore) janitor() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.janitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.RemoveExpired()
+		}
+	}
+}
+
+// shard returns the shard holding the nonces of id, selected by its FNV-1a hash.
+func (s *RAMStore) shard(id string) *ramShard {
+	hash := uint32(2166136261)
+	for i := 0; i < len(id); i++ {
+		hash ^= uint32(id[i])
+		hash *= 16777619
+	}
+	return &s.shards[hash%uint32(len(s.shards))]
+}
+
+// removeExpired removes the expired nonces of the shard, which must be locked, and returns their number.
+func (s *ramShard) removeExpired(now time.Time, ttl time.Duration) int {
+	var removed int
+	for id := range s.nonces {
+		removed += s.remove(id, func(n Nonce) bool { return expired(n, now, ttl) })
+	}
+	return removed
+}
+
+// removeBefore removes the nonces of id created before notBefore from the shard, which must be locked, and
+// returns their number.
+func (s *ramShard) removeBefore(id string, notBefore time.Time) int {
+	return s.remove(id, func(n Nonce) bool { return n.CreatedAt.Before(notBefore) })
+}
+
+// remove removes the nonces of id matching removed from the shard, which must be locked, and returns their number.
+func (s *ramShard) remove(id string, removed func(Nonce) bool) int {
+	nonces := s.nonces[id]
+	kept := nonces[:0]
+	for _, n := range nonces {
+		if !removed(n) {
+			kept = append(kept, n)
+		}
+	}
+	if len(kept) == 0 {
+		delete(s.nonces, id)
+		return len(nonces)
+	}
+	// clear the removed nonces, so their values can be collected
+	for i := len(kept); i < len(nonces); i++ {
+		nonces[i] = Nonce{}
+	}
+	s.nonces[id] = kept
+	return len(nonces) - len(kept)
+}
+
+// expired returns whether the nonce TTL elapsed at now.
+func expired(nonce Nonce, now time.Time, ttl time.Duration) bool {
+	return !now.Before(nonce.CreatedAt.Add(ttl))
+}
@@ -0,0 +1,246 @@
+package antireplay
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a clock set by the tests.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestRAMStore(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	store := NewRAMStore(WithTTL(time.Minute), WithClock(clock.Now))
+	defer store.Stop()
+
+	n1 := Nonce{ID: "id1", Value: []byte{1}, CreatedAt: clock.Now()}
+	n2 := Nonce{ID: "id1", Value: []byte{2}, CreatedAt: clock.Now().Add(30 * time.Second)}
+	n3 := Nonce{ID: "id2", Value: []byte{1}, CreatedAt: clock.Now()}
+	for _, n := range []Nonce{n1, n2, n3} {
+		require.NoError(t, store.Insert(n))
+	}
+
+	nonces, err := store.Get("id1")
+	require.NoError(t, err)
+	require.Equal(t, []Nonce{n1, n2}, nonces)
+	nonces, err = store.Get("unknown")
+	require.NoError(t, err)
+	require.Empty(t, nonces)
+
+	clock.Add(time.Minute)
+	nonces, err = store.Get("id1")
+	require.NoError(t, err)
+	require.Equal(t, []Nonce{n2}, nonces, "expired nonces are never returned")
+	require.Equal(t, 3, store.Len(), "expired nonces are kept until removed")
+
+	store.RemoveExpired()
+	require.Equal(t, 1, store.Len())
+	nonces, err = store.Get("id2")
+	require.NoError(t, err)
+	require.Empty(t, nonces)
+}
+
+func TestRAMStoreInsertIfAbsent(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	store := NewRAMStore(WithTTL(time.Minute), WithClock(clock.Now))
+	defer store.Stop()
+
+	n1 := Nonce{ID: "id1", Value: []byte{1}, CreatedAt: clock.Now()}
+	require.NoError(t, store.InsertIfAbsent(n1, time.Minute))
+	require.Equal(t, ErrReplay, store.InsertIfAbsent(n1, time.Minute))
+	require.NoError(t, store.InsertIfAbsent(Nonce{ID: "id1", Value: []byte{2}, CreatedAt: clock.Now()}, time.Minute))
+	require.NoError(t, store.InsertIfAbsent(Nonce{ID: "id2", Value: []byte{1}, CreatedAt: clock.Now()}, time.Minute))
+
+	clock.Add(30 * time.Second)
+	require.NoError(t, store.InsertIfAbsent(n1, 10*time.Second), "nonces older than maxAge are ignored")
+
+	clock.Add(time.Minute)
+	require.NoError(t, store.InsertIfAbsent(n1, time.Hour), "expired nonces are ignored")
+}
+
+func TestRAMStoreMaxNonces(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	store := NewRAMStore(WithTTL(time.Minute), WithShards(1), WithMaxNonces(2), WithClock(clock.Now))
+	defer store.Stop()
+
+	require.NoError(t, store.Insert(Nonce{ID: "id1", Value: []byte{1}, CreatedAt: clock.Now()}))
+	require.NoError(t, store.Insert(Nonce{ID: "id2", Value: []byte{1}, CreatedAt: clock.Now().Add(time.Second)}))
+	require.Equal(t, ErrStoreFull, store.Insert(Nonce{ID: "id3", Value: []byte{1}, CreatedAt: clock.Now()}))
+
+	clock.Add(time.Minute)
+	require.NoError(t, store.Insert(Nonce{ID: "id3", Value: []byte{1}, CreatedAt: clock.Now()}), "expired nonces are removed from full stores")
+	require.Equal(t, 2, store.Len())
+}
+
+func TestRAMStoreMaxNoncesAcrossShards(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	store := NewRAMStore(WithTTL(time.Hour), WithShards(64), WithMaxNonces(100), WithClock(clock.Now))
+	defer store.Stop()
+
+	// a single ID can use the whole store, whatever its shard
+	for i := 0; i < 100; i++ {
+		require.NoError(t, store.InsertIfAbsent(Nonce{ID: "hot", Value: []byte{byte(i)}, CreatedAt: clock.Now()}, time.Minute))
+	}
+	require.Equal(t, 100, store.Len())
+	require.Equal(t, ErrStoreFull, store.InsertIfAbsent(Nonce{ID: "hot", Value: []byte{100}, CreatedAt: clock.Now()}, time.Minute))
+	require.Equal(t, ErrStoreFull, store.InsertIfAbsent(Nonce{ID: "other", Value: []byte{1}, CreatedAt: clock.Now()}, time.Minute))
+
+	clock.Add(2 * time.Minute)
+	require.Equal(t, ErrStoreFull, store.InsertIfAbsent(Nonce{ID: "other", Value: []byte{1}, CreatedAt: clock.Now()}, time.Minute),
+		"the nonces of other IDs are kept until they expire")
+	require.NoError(t, store.InsertIfAbsent(Nonce{ID: "hot", Value: []byte{1}, CreatedAt: clock.Now()}, time.Minute),
+		"the nonces of the ID older than maxAge are removed from full stores")
+	require.Equal(t, 1, store.Len())
+	require.NoError(t, store.InsertIfAbsent(Nonce{ID: "other", Value: []byte{1}, CreatedAt: clock.Now()}, time.Minute))
+	require.Equal(t, ErrReplay, store.InsertIfAbsent(Nonce{ID: "hot", Value: []byte{1}, CreatedAt: clock.Now()}, time.Minute))
+}
+
+func TestRAMStoreMaxNoncesConcurrency(t *testing.T) {
+	store := NewRAMStore(WithShards(8), WithMaxNonces(100))
+	defer store.Stop()
+	now := time.Now()
+
+	var inserted int64
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				err := store.Insert(Nonce{ID: fmt.Sprintf("id%d-%d", w, i), Value: []byte{1}, CreatedAt: now})
+				if err == nil {
+					atomic.AddInt64(&inserted, 1)
+				} else if err != ErrStoreFull {
+					t.Error(err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	require.Equal(t, int64(100), inserted, "the store holds at most its max nonces")
+	require.Equal(t, 100, store.Len())
+}
+
+func TestRAMStoreJanitor(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	store := NewRAMStore(WithTTL(time.Minute), WithJanitorInterval(time.Millisecond), WithClock(clock.Now))
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, store.Insert(Nonce{ID: fmt.Sprintf("id%d", i), Value: []byte{1}, CreatedAt: clock.Now()}))
+	}
+	require.Equal(t, 100, store.Len())
+
+	clock.Add(time.Minute)
+	require.Eventually(t, func() bool { return store.Len() == 0 }, time.Second, time.Millisecond)
+
+	store.Stop()
+	store.Stop()
+	require.NoError(t, store.Insert(Nonce{ID: "id1", Value: []byte{1}, CreatedAt: clock.Now()}), "stopped stores can still be used")
+}
+
+func TestRAMStoreStopBeforeInsert(t *testing.T) {
+	store := NewRAMStore()
+	store.Stop()
+	require.NoError(t, store.Insert(Nonce{ID: "id1", Value: []byte{1}, CreatedAt: time.Now()}))
+	require.Equal(t, 1, store.Len())
+}
+
+func TestRAMStoreConcurrency(t *testing.T) {
+	store := NewRAMStore(WithShards(4), WithJanitorInterval(time.Millisecond))
+	defer store.Stop()
+
+	const workers = 16
+	const noncesPerWorker = 200
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < noncesPerWorker; i++ {
+				id := fmt.Sprintf("id%d", i%10)
+				if err := store.Insert(Nonce{ID: id, Value: []byte{byte(w), byte(i)}, CreatedAt: now}); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := store.Get(id); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	require.Equal(t, workers*noncesPerWorker, store.Len())
+	var total int
+	for i := 0; i < 10; i++ {
+		nonces, err := store.Get(fmt.Sprintf("id%d", i))
+		require.NoError(t, err)
+		total += len(nonces)
+	}
+	require.Equal(t, workers*noncesPerWorker, total)
+}
+
+func BenchmarkRAMStoreInsert(b *testing.B) {
+	store := NewRAMStore(WithMaxNonces(b.N + 1))
+	defer store.Stop()
+	now := time.Now()
+
+	var i int64
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := atomic.AddInt64(&i, 1)
+			if err := store.Insert(Nonce{ID: fmt.Sprintf("user%d@email.com", n%1000), Value: []byte{byte(n)}, CreatedAt: now}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkRAMStoreGet(b *testing.B) {
+	store := NewRAMStore()
+	defer store.Stop()
+	now := time.Now()
+	for i := 0; i < 100000; i++ {
+		if err := store.Insert(Nonce{ID: fmt.Sprintf("user%d@email.com", i%1000), Value: []byte{byte(i)}, CreatedAt: now}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	var i int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := atomic.AddInt64(&i, 1)
+			if _, err := store.Get(fmt.Sprintf("user%d@email.com", n%1000)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package antireplay
 
-import "time"
+import (
+	"bytes"
+	"sync"
+	"time"
+)
 
 type Nonce struct {
 	ID        string
@@ -8,30 +12,61 @@ type Nonce struct {
 	CreatedAt time.Time
 }
 
+// Store holds the nonces already used, by ID. Implementations must be safe for concurrent use, as calls are
+// checked concurrently, see RAMStore.
 type Store interface {
 	Insert(nonce Nonce) error
 	Get(ID string) ([]Nonce, error)
+	// InsertIfAbsent inserts nonce unless the store already holds a nonce with the same ID and value, created
+	// less than maxAge ago, in which case it returns ErrReplay. The check and the insertion must be atomic, so
+	// two concurrent calls with the same nonce can't both succeed.
+	InsertIfAbsent(nonce Nonce, maxAge time.Duration) error
 }
 
-type ramStore struct {
-	store []Nonce
+// LegacyStore is a store without the atomic InsertIfAbsent operation, see NewLegacyStoreAdapter.
+type LegacyStore interface {
+	Insert(nonce Nonce) error
+	Get(ID string) ([]Nonce, error)
+}
+
+// NewLegacyStoreAdapter adapts a LegacyStore to the Store interface. Its InsertIfAbsent gets the nonces of the ID,
+// and then inserts the nonce when none matches, holding a lock so the checks of this process are serialized.
+// The lock doesn't span processes: when several servers share the legacy store, the same nonce replayed
+// concurrently on two of them can pass both checks. Stores shared between servers must implement InsertIfAbsent
+// atomically instead, i.e., with a unique constraint on the ID and value.
+func NewLegacyStoreAdapter(store LegacyStore) Store {
+	return &legacyStoreAdapter{LegacyStore: store}
 }
 
-func NewRAMStore() Store {
-	return &ramStore{}
+type legacyStoreAdapter struct {
+	LegacyStore
+	mu sync.Mutex
 }
 
-func (s *ramStore) Insert(nonce Nonce) error {
-	s.store = append(s.store, nonce)
-	return nil
+func (s *legacyStoreAdapter) InsertIfAbsent(nonce Nonce, maxAge time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existingNonces, err := s.Get(nonce.ID)
+	if err != nil {
+		return err
+	}
+	if containsNonce(existingNonces, nonce, time.Now().Add(-maxAge)) {
+		return ErrReplay
+	}
+	return s.Insert(nonce)
 }
 
-func (s *ramStore) Get(id string) ([]Nonce, error) {
-	var userNonces []Nonce
-	for _, n := range s.store {
-		if n.ID == id {
-			userNonces = append(userNonces, n)
+// containsNonce returns whether nonces holds the value of nonce, skipping the ones created before notBefore.
+func containsNonce(nonces []Nonce, nonce Nonce, notBefore time.Time) bool {
+	for _, n := range nonces {
+		// skip too old nonces
+		if n.CreatedAt.Before(notBefore) {
+			continue
+		}
+		if bytes.Equal(n.Value, nonce.Value) {
+			return true
 		}
 	}
-	return userNonces, nil
+	return false
 }
@@ -0,0 +1,84 @@
+package authorization
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// Audience is the audience a signed biscuit is issued for, with the public key verifying its audience signature.
+type Audience struct {
+	ID        string
+	PublicKey *ecdsa.PublicKey
+}
+
+// ParseAudiencePublicKey parses a DER encoded PKIX ECDSA public key, as written by cmd/keys.
+func ParseAudiencePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	pubkey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaPubKey, ok := pubkey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("authorization: unsupported audience public key type %T", pubkey)
+	}
+	return ecdsaPubKey, nil
+}
+
+// AudienceRegistry resolves the audience expected on each call. Services can be bound to their own audience,
+// and every other service falls back to the default one.
+type AudienceRegistry struct {
+	defaultAudience *Audience
+	services        map[string]Audience
+}
+
+// NewAudienceRegistry creates a registry falling back to defaultAudience, and binding the services
+// in serviceAudiences (indexed by their full name, i.e., package.service) to their own audience.
+// defaultAudience can be nil, in which case calls to unlisted services are rejected.
+func NewAudienceRegistry(defaultAudience *Audience, serviceAudiences map[string]Audience) (*AudienceRegistry, error) {
+	if defaultAudience == nil && len(serviceAudiences) == 0 {
+		return nil, errors.New("authorization: at least one audience is required")
+	}
+
+	if defaultAudience != nil {
+		if err := defaultAudience.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	services := make(map[string]Audience, len(serviceAudiences))
+	for service, audience := range serviceAudiences {
+		if err := audience.validate(); err != nil {
+			return nil, fmt.Errorf("%w for service %q", err, service)
+		}
+		services[service] = audience
+	}
+
+	return &AudienceRegistry{
+		defaultAudience: defaultAudience,
+		services:        services,
+	}, nil
+}
+
+// Lookup returns the audience of the given service.
+func (r *AudienceRegistry) Lookup(service string) (Audience, error) {
+	if audience, ok := r.services[service]; ok {
+		return audience, nil
+	}
+	if r.defaultAudience != nil {
+		return *r.defaultAudience, nil
+	}
+	return Audience{}, fmt.Errorf("authorization: no audience registered for service %q", service)
+}
+
+func (a Audience) validate() error {
+	if a.ID == "" {
+		return errors.New("authorization: audience ID is required")
+	}
+	if a.PublicKey == nil {
+		return fmt.Errorf("authorization: audience %q public key is required", a.ID)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package authorization
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudienceRegistryLookup(t *testing.T) {
+	defaultKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serviceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	defaultAudience := &Audience{ID: "http://default.local", PublicKey: &defaultKey.PublicKey}
+	serviceAudience := Audience{ID: "http://service.local", PublicKey: &serviceKey.PublicKey}
+
+	registry, err := NewAudienceRegistry(defaultAudience, map[string]Audience{"demo.api.v1.Service": serviceAudience})
+	require.NoError(t, err)
+
+	audience, err := registry.Lookup("demo.api.v1.Service")
+	require.NoError(t, err)
+	require.Equal(t, serviceAudience, audience)
+
+	audience, err = registry.Lookup("demo.api.v1.Other")
+	require.NoError(t, err)
+	require.Equal(t, *defaultAudience, audience)
+
+	registry, err = NewAudienceRegistry(nil, map[string]Audience{"demo.api.v1.Service": serviceAudience})
+	require.NoError(t, err)
+	_, err = registry.Lookup("demo.api.v1.Other")
+	require.Error(t, err)
+
+	_, err = NewAudienceRegistry(nil, nil)
+	require.Error(t, err)
+	_, err = NewAudienceRegistry(&Audience{ID: "http://nokey.local"}, nil)
+	require.Error(t, err)
+	_, err = NewAudienceRegistry(nil, map[string]Audience{"demo.api.v1.Service": {PublicKey: &serviceKey.PublicKey}})
+	require.Error(t, err)
+}
+
+func TestParseAudiencePublicKey(t *testing.T) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+	require.NoError(t, err)
+
+	pubKey, err := ParseAudiencePublicKey(der)
+	require.NoError(t, err)
+	require.True(t, privKey.PublicKey.Equal(pubKey))
+
+	_, err = ParseAudiencePublicKey([]byte("not a key"))
+	require.Error(t, err)
+}
@@ -0,0 +1,276 @@
+package authorization
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/flynn/biscuit-go/datalog"
+)
+
+// CacheConfig configures the authorization cache of the server interceptor.
+type CacheConfig struct {
+	// Size is the maximum number of entries of the token cache, and of the decision cache.
+	Size int
+	// TTL is the maximum duration an entry is kept. Entries never outlive their token either, whose expiration
+	// is read from the date constraints of its caveats.
+	TTL time.Duration
+}
+
+func (c CacheConfig) validate() error {
+	if c.Size <= 0 {
+		return errors.New("authorization: cache size must be positive")
+	}
+	if c.TTL <= 0 {
+		return errors.New("authorization: cache TTL must be positive")
+	}
+	return nil
+}
+
+// authorizationCache caches the root keys of the verified tokens by base token hash, and the authorization
+// decisions by base token hash, method and ambient facts. A nil cache never hits, and tokens without hash are
+// never cached.
+type authorizationCache struct {
+	tokens    *lruCache
+	decisions *lruCache
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// verifiedToken is a base token whose signatures have been verified with rootKey.
+type verifiedToken struct {
+	rootKey RootKey
+	// expiresAt is the expiration of the token, zero when its caveats don't bound its lifetime.
+	expiresAt time.Time
+}
+
+func newAuthorizationCache(cfg CacheConfig, now func() time.Time) *authorizationCache {
+	return &authorizationCache{
+		tokens:    newLRUCache(cfg.Size, now),
+		decisions: newLRUCache(cfg.Size, now),
+		ttl:       cfg.TTL,
+		now:       now,
+	}
+}
+
+// baseTokenHash returns the hash of the base token of b verified for the audience, used as cache key. The base
+// token holds every block but the last one, which is the user signature block appended by the client on each call,
+// with a new nonce. It returns an empty hash when b can't be cached: when it has no signature block, or when its
+// signature block holds caveats, which could change between calls.
+func baseTokenHash(audienceID string, b *biscuit.Biscuit) string {
+	count := b.BlockCount()
+	if count == 0 {
+		return ""
+	}
+	if caveats := b.Caveats(); len(caveats[len(caveats)-1]) > 0 {
+		return ""
+	}
+	sum, err := b.SHA256Sum(count - 1)
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.New()
+	h.Write([]byte(audienceID))
+	h.Write([]byte{0})
+	h.Write(sum)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// tokenExpiry returns the time from which the caveats of the base token of b can't hold anymore, or zero when they
+// don't bound its lifetime. A caveat is bound by the latest bound of its queries, and a query by the earliest date
+// of its $0 <= date constraints, such as the expiration caveat of signed biscuits.
+func tokenExpiry(b *biscuit.Biscuit) time.Time {
+	caveats := b.Caveats()
+	var expiry time.Time
+	for _, blockCaveats := range caveats[:len(caveats)-1] {
+		for _, caveat := range blockCaveats {
+			if t := caveatExpiry(caveat); !t.IsZero() && (expiry.IsZero() || t.Before(expiry)) {
+				expiry = t
+			}
+		}
+	}
+	return expiry
+}
+
+// caveatExpiry returns the time from which the caveat can't hold anymore, or zero when one of its queries
+// isn't bound.
+func caveatExpiry(caveat datalog.Caveat) time.Time {
+	var expiry time.Time
+	for _, query := range caveat.Queries {
+		var bound time.Time
+		for _, constraint := range query.Constraints {
+			checker, ok := constraint.Checker.(datalog.DateComparisonChecker)
+			if !ok || checker.Comparison != datalog.DateComparisonBefore {
+				continue
+			}
+			if t := time.Unix(int64(checker.Date), 0); bound.IsZero() || t.Before(bound) {
+				bound = t
+			}
+		}
+		if bound.IsZero() {
+			return time.Time{}
+		}
+		if bound.After(expiry) {
+			expiry = bound
+		}
+	}
+	return expiry
+}
+
+func (c *authorizationCache) getToken(hash string) (*verifiedToken, bool) {
+	if c == nil || hash == "" {
+		return nil, false
+	}
+
+	v, ok := c.tokens.get(hash)
+	if !ok {
+		return nil, false
+	}
+	return v.(*verifiedToken), true
+}
+
+func (c *authorizationCache) addToken(hash string, token *verifiedToken) {
+	if c == nil || hash == "" {
+		return
+	}
+
+	c.tokens.add(hash, token, c.expiresAt(token))
+}
+
+// getDecision returns the cached authorization decision of the token on a call to fullMethod with the given
+// ambient facts. The decision is nil when the call was authorized, or the denial error.
+func (c *authorizationCache) getDecision(hash, fullMethod string, facts []biscuit.Fact) (decision error, ok bool) {
+	if c == nil || hash == "" {
+		return nil, false
+	}
+
+	v, ok := c.decisions.get(decisionKey(hash, fullMethod, facts))
+	if !ok {
+		return nil, false
+	}
+	if v == nil {
+		return nil, true
+	}
+	return v.(error), true
+}
+
+func (c *authorizationCache) addDecision(hash, fullMethod string, facts []biscuit.Fact, token *verifiedToken, decision error) {
+	if c == nil || hash == "" {
+		return
+	}
+
+	var value interface{}
+	if decision != nil {
+		value = decision
+	}
+	c.decisions.add(decisionKey(hash, fullMethod, facts), value, c.expiresAt(token))
+}
+
+// expiresAt returns the expiration time of the token entries.
+func (c *authorizationCache) expiresAt(token *verifiedToken) time.Time {
+	expiresAt := c.now().Add(c.ttl)
+	if !token.expiresAt.IsZero() && token.expiresAt.Before(expiresAt) {
+		expiresAt = token.expiresAt
+	}
+	return expiresAt
+}
+
+// decisionKey returns the cache key of a decision, from the token hash, the method, and a canonical hash of the facts.
+func decisionKey(hash, fullMethod string, facts []biscuit.Fact) string {
+	strFacts := make([]string, 0, len(facts))
+	for _, f := range facts {
+		strFacts = append(strFacts, f.String())
+	}
+	sort.Strings(strFacts)
+
+	h := sha256.New()
+	for _, f := range strFacts {
+		h.Write([]byte(f))
+		h.Write([]byte{0})
+	}
+
+	return strings.Join([]string{hash, fullMethod, hex.EncodeToString(h.Sum(nil))}, "/")
+}
+
+// lruCache is a thread safe, bounded, least recently used cache, whose entries expire.
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newLRUCache(size int, now func() time.Time) *lruCache {
+	return &lruCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+		now:   now,
+	}
+}
+
+func (c *lruCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elt, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elt.Value.(*lruEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(elt)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elt)
+	return entry.value, true
+}
+
+func (c *lruCache) add(key string, value interface{}, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.now().Before(expiresAt) {
+		return
+	}
+
+	if elt, ok := c.items[key]; ok {
+		entry := elt.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elt)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *lruCache) remove(elt *list.Element) {
+	c.order.Remove(elt)
+	delete(c.items, elt.Value.(*lruEntry).key)
+}
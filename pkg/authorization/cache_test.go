@@ -0,0 +1,274 @@
+package authorization
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/flynn/biscuit-go/sig"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Now()
+	c := newLRUCache(2, func() time.Time { return now })
+
+	c.add("a", 1, now.Add(time.Minute))
+	c.add("b", 2, now.Add(time.Minute))
+
+	v, ok := c.get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+
+	// b is now the least recently used entry, and gets evicted
+	c.add("c", 3, now.Add(time.Minute))
+	require.Equal(t, 2, c.len())
+	_, ok = c.get("b")
+	require.False(t, ok)
+	_, ok = c.get("a")
+	require.True(t, ok)
+	_, ok = c.get("c")
+	require.True(t, ok)
+
+	// updating an entry doesn't grow the cache
+	c.add("c", 4, now.Add(time.Minute))
+	require.Equal(t, 2, c.len())
+	v, ok = c.get("c")
+	require.True(t, ok)
+	require.Equal(t, 4, v)
+
+	// nil values are cached
+	c.add("nil", nil, now.Add(time.Minute))
+	v, ok = c.get("nil")
+	require.True(t, ok)
+	require.Nil(t, v)
+
+	// expired entries are never returned
+	c.add("expired", 5, now)
+	_, ok = c.get("expired")
+	require.False(t, ok)
+
+	c.add("d", 6, now.Add(time.Second))
+	now = now.Add(time.Second)
+	_, ok = c.get("d")
+	require.False(t, ok)
+}
+
+func TestDecisionKey(t *testing.T) {
+	service := biscuit.Fact{Predicate: biscuit.Predicate{Name: "service", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String("demo.api.v1.Demo")}}}
+	method := biscuit.Fact{Predicate: biscuit.Predicate{Name: "method", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String("Read")}}}
+	argDev := biscuit.Fact{Predicate: biscuit.Predicate{Name: "arg", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String("env"), biscuit.String("DEV")}}}
+	argPrd := biscuit.Fact{Predicate: biscuit.Predicate{Name: "arg", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String("env"), biscuit.String("PRD")}}}
+
+	key := decisionKey("hash", "/demo.api.v1.Demo/Read", []biscuit.Fact{service, method, argDev})
+	require.Equal(t, key, decisionKey("hash", "/demo.api.v1.Demo/Read", []biscuit.Fact{argDev, method, service}), "facts order must not matter")
+	require.NotEqual(t, key, decisionKey("hash", "/demo.api.v1.Demo/Read", []biscuit.Fact{service, method, argPrd}))
+	require.NotEqual(t, key, decisionKey("hash", "/demo.api.v1.Demo/Update", []biscuit.Fact{service, method, argDev}))
+	require.NotEqual(t, key, decisionKey("other", "/demo.api.v1.Demo/Read", []biscuit.Fact{service, method, argDev}))
+}
+
+func TestBaseTokenHash(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `policy "empty" {}`))
+
+	signed1, err := biscuit.Unmarshal(iss.sign(t, token))
+	require.NoError(t, err)
+	signed2, err := biscuit.Unmarshal(iss.sign(t, token))
+	require.NoError(t, err)
+
+	hash := baseTokenHash(testAudience, signed1)
+	require.NotEmpty(t, hash)
+	require.Equal(t, hash, baseTokenHash(testAudience, signed2), "user signatures are not part of the hash")
+	require.NotEqual(t, hash, baseTokenHash("http://other.local", signed1))
+
+	other, err := biscuit.Unmarshal(iss.sign(t, iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `policy "empty" {}`))))
+	require.NoError(t, err)
+	require.NotEqual(t, hash, baseTokenHash(testAudience, other))
+
+	unsigned, err := biscuit.Unmarshal(token)
+	require.NoError(t, err)
+	require.Empty(t, baseTokenHash(testAudience, unsigned), "tokens without signature block are never cached")
+}
+
+func TestTokenExpiry(t *testing.T) {
+	iss := newTestIssuer(t)
+	expireTime := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		policy string
+		expiry time.Time
+	}{
+		{policy: `policy "empty" {}`, expiry: expireTime},
+		{
+			policy: `policy "short" {
+				caveats {[
+					*not_expired($0)
+						<-  time(#ambient, $0)
+						@   $0 <= "2021-03-04T12:00:00Z"
+				]}
+			}`,
+			expiry: time.Date(2021, 3, 4, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			policy: `policy "unbound" {
+				caveats {[
+					*not_expired($0)
+						<-  time(#ambient, $0)
+						@   $0 <= "2021-03-04T12:00:00Z"
+				||
+					*break_glass()
+						<-  break_glass(#authority)
+				]}
+			}`,
+			expiry: expireTime,
+		},
+		{
+			policy: `policy "latest" {
+				caveats {[
+					*not_expired($0)
+						<-  time(#ambient, $0)
+						@   $0 <= "2021-03-04T12:00:00Z"
+				||
+					*not_expired($0)
+						<-  time(#ambient, $0)
+						@   $0 <= "2021-03-05T12:00:00Z"
+				]}
+			}`,
+			expiry: time.Date(2021, 3, 5, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, testCase := range testCases {
+		b, err := biscuit.Unmarshal(iss.sign(t, iss.baseToken(t, expireTime, parsePolicy(t, testCase.policy))))
+		require.NoError(t, err)
+		require.True(t, testCase.expiry.Equal(tokenExpiry(b)), "policy %s: expiry %s", testCase.policy, tokenExpiry(b))
+	}
+}
+
+func TestUnaryAuthorizationCache(t *testing.T) {
+	iss := newTestIssuer(t)
+	expireTime := time.Now().Add(time.Hour).Truncate(time.Second)
+	token := iss.baseToken(t, expireTime, parsePolicy(t, `
+		policy "token" {
+			caveats {[
+				*allowed_name($0)
+					<-  arg(#ambient, "name", $0)
+					@   $0 in ["obj1"]
+			]}
+		}
+	`))
+
+	now := time.Now()
+	core, logs := observer.New(zapcore.DebugLevel)
+	i := iss.interceptor(t,
+		WithAuthorizationCache(CacheConfig{Size: 16, TTL: 2 * time.Hour}),
+		WithLogger(zap.New(core)),
+		WithClock(func() time.Time { return now }),
+	)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/authorization.test.Service/Method"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, ok := CallerFromContext(ctx)
+		require.True(t, ok)
+		return &prototesting.Object{}, nil
+	}
+	cacheHits := func() int {
+		return logs.FilterMessage("authorization cache hit").Len()
+	}
+
+	for n := 0; n < 3; n++ {
+		_, err := i.Unary(iss.signedContext(t, token), &prototesting.Object{Name: "obj1"}, info, handler)
+		require.NoError(t, err)
+		require.Equal(t, n, cacheHits(), "calls signed with new nonces hit the cache")
+	}
+	for n := 0; n < 2; n++ {
+		_, err := i.Unary(iss.signedContext(t, token), &prototesting.Object{Name: "obj2"}, info, handler)
+		require.True(t, errors.Is(err, ErrNotAuthorized))
+	}
+	require.Equal(t, 3, cacheHits(), "denials are cached")
+	require.Equal(t, 1, i.cache.tokens.len())
+	require.Equal(t, 2, i.cache.decisions.len())
+
+	ctx := iss.signedContext(t, token)
+	_, err := i.Unary(ctx, &prototesting.Object{Name: "obj1"}, info, handler)
+	require.NoError(t, err)
+	_, err = i.Unary(ctx, &prototesting.Object{Name: "obj1"}, info, handler)
+	require.True(t, errors.Is(err, ErrReplay), "nonces are checked on cache hits")
+
+	md, _ := metadata.FromIncomingContext(iss.signedContext(t, token))
+	signed, err := base64.URLEncoding.DecodeString(md[MetadataAuthorization][0])
+	require.NoError(t, err)
+	b, err := biscuit.Unmarshal(signed)
+	require.NoError(t, err)
+	hash := baseTokenHash(testAudience, b)
+	_, ok := i.cache.getToken(hash)
+	require.True(t, ok)
+
+	now = expireTime
+	_, ok = i.cache.getToken(hash)
+	require.False(t, ok, "entries never outlive their token")
+	hits := cacheHits()
+	_, err = i.Unary(iss.signedContext(t, token), &prototesting.Object{Name: "obj1"}, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, hits, cacheHits())
+}
+
+func TestUnaryAuthorizationCacheVerifications(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `policy "empty" {}`))
+
+	var verifications int
+	defer func(verify func(*biscuit.Biscuit, sig.PublicKey) (biscuit.Verifier, error)) { verifyToken = verify }(verifyToken)
+	verifyToken = func(b *biscuit.Biscuit, rootKey sig.PublicKey) (biscuit.Verifier, error) {
+		verifications++
+		return b.Verify(rootKey)
+	}
+
+	// the token root key is the second one of the keyring, selected after failing to verify the token with the first
+	keyring, err := NewRootKeyring(
+		RootKey{ID: "k1", PublicKey: sig.GenerateKeypair(rand.Reader).Public()},
+		RootKey{ID: "k2", PublicKey: iss.rootKey.Public()},
+	)
+	require.NoError(t, err)
+	info := &grpc.UnaryServerInfo{FullMethod: "/authorization.test.Service/Method"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &prototesting.Object{}, nil
+	}
+
+	testCases := []struct {
+		name string
+		opts []ServerInterceptorOption
+		// verifications are the expected verifications of the first call, and of each following one
+		first, next int
+	}{
+		{name: "without cache", first: 2, next: 2},
+		{name: "with cache", opts: []ServerInterceptorOption{WithAuthorizationCache(CacheConfig{Size: 16, TTL: time.Hour})}, first: 2, next: 1},
+	}
+	for _, testCase := range testCases {
+		opts := append([]ServerInterceptorOption{WithAudience(testAudience, &iss.audienceKey.PublicKey), WithRootKeyring(keyring)}, testCase.opts...)
+		i, err := NewBiscuitServerInterceptor(nil, opts...)
+		require.NoError(t, err)
+
+		for n := 0; n < 3; n++ {
+			verifications = 0
+			_, err := i.Unary(iss.signedContext(t, token), &prototesting.Object{Name: "obj1"}, info, handler)
+			require.NoError(t, err)
+
+			expected := testCase.next
+			if n == 0 {
+				expected = testCase.first
+			}
+			require.Equal(t, expected, verifications, "%s: call #%d", testCase.name, n)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/flynn/biscuit-go/cookbook/signedbiscuit"
+)
+
+// Caller is the verified identity of the caller, set by the server interceptor in the handler context.
+type Caller struct {
+	UserID             string
+	UserEmail          string
+	ClientID           string
+	IssueTime          time.Time
+	SignatureTimestamp time.Time
+	SignatureNonce     []byte
+	// RootKeyID is the ID of the root key which signed the token.
+	RootKeyID string
+	// Biscuit is the verified caller token.
+	Biscuit *biscuit.Biscuit
+
+	checker *callChecker
+}
+
+type callerContextKey struct{}
+
+// CallerFromContext returns the caller verified by the server interceptor, or false when
+// the context doesn't hold any, such as on exempt methods.
+func CallerFromContext(ctx context.Context) (*Caller, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(*Caller)
+	return caller, ok
+}
+
+func newCallerContext(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+func newCaller(signatureMetas *signedbiscuit.UserSignatureMetadata, checker *callChecker) *Caller {
+	return &Caller{
+		UserID:             signatureMetas.UserID,
+		UserEmail:          signatureMetas.UserEmail,
+		ClientID:           signatureMetas.ClientID,
+		IssueTime:          signatureMetas.IssueTime,
+		SignatureTimestamp: signatureMetas.UserSignatureTimestamp,
+		SignatureNonce:     signatureMetas.UserSignatureNonce,
+		RootKeyID:          checker.verifier.rootKey.ID,
+		Biscuit:            checker.verifier.biscuit,
+		checker:            checker,
+	}
+}
+
+// Verifier returns a new verifier on the caller token, to run extra queries on its facts and rules.
+// It doesn't hold the ambient facts of the call.
+func (c *Caller) Verifier() (biscuit.Verifier, error) {
+	return c.Biscuit.Verify(c.checker.verifier.rootKey.PublicKey)
+}
@@ -0,0 +1,28 @@
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallerFromContext(t *testing.T) {
+	_, ok := CallerFromContext(context.Background())
+	require.False(t, ok)
+
+	caller := &Caller{
+		UserID:    "userID",
+		UserEmail: "user@email.com",
+	}
+
+	ctx := newCallerContext(context.Background(), caller)
+	got, ok := CallerFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, caller, got)
+
+	stream := &authorizedServerStream{ctx: ctx}
+	got, ok = CallerFromContext(stream.Context())
+	require.True(t, ok)
+	require.Equal(t, caller, got)
+}
@@ -0,0 +1,76 @@
+package authorization
+
+import (
+	"context"
+	"demo/pkg/policy"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/flynn/biscuit-go"
+)
+
+// Check authorizes an action of the handler, with the verified token of the call held by ctx. The token rules and
+// caveats, along with the verifier policies selected for the call, are verified again with the ambient facts of the
+// call, plus the given facts, such as owner(#ambient, "entity1", "userID") once the handler loaded the entity.
+// When policyName isn't empty, the rules and caveats of this verifier policy are also added to the verifier.
+// On streaming calls, the ambient facts are the ones of the last received message.
+func Check(ctx context.Context, policyName string, facts ...biscuit.Fact) error {
+	caller, ok := CallerFromContext(ctx)
+	if !ok {
+		return ErrMissingToken.wrap(errors.New("no verified caller in context"))
+	}
+
+	return caller.checker.check(policyName, facts)
+}
+
+// callChecker runs the handler checks of a call.
+type callChecker struct {
+	verifier   *grpcVerifier
+	fullMethod string
+
+	mu    sync.Mutex
+	facts []biscuit.Fact
+}
+
+func newCallChecker(verifier *grpcVerifier, fullMethod string, facts []biscuit.Fact) *callChecker {
+	return &callChecker{
+		verifier:   verifier,
+		fullMethod: fullMethod,
+		facts:      facts,
+	}
+}
+
+// setFacts replaces the ambient facts of the call.
+func (c *callChecker) setFacts(facts []biscuit.Fact) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.facts = facts
+}
+
+// ambientFacts returns a copy of the ambient facts of the call.
+func (c *callChecker) ambientFacts() []biscuit.Fact {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]biscuit.Fact(nil), c.facts...)
+}
+
+func (c *callChecker) check(policyName string, extraFacts []biscuit.Fact) error {
+	var extraPolicies []policy.Policy
+	if policyName != "" {
+		p, ok := c.verifier.policies[policyName]
+		if !ok {
+			return ErrInternal.wrap(fmt.Errorf("unknown verifier policy %q", policyName))
+		}
+		extraPolicies = append(extraPolicies, p)
+	}
+
+	facts := append(c.ambientFacts(), extraFacts...)
+
+	verifier, _, err := c.verifier.newSignedVerifier()
+	if err != nil {
+		return err
+	}
+
+	return c.verifier.authorize(verifier, c.fullMethod, subjectCheck, facts, extraPolicies...)
+}
@@ -0,0 +1,90 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"demo/pkg/policy"
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestCheckRequiresCaller(t *testing.T) {
+	err := Check(context.Background(), "")
+	require.True(t, errors.Is(err, ErrMissingToken))
+}
+
+func TestCheckUnknownPolicy(t *testing.T) {
+	checker := newCallChecker(&grpcVerifier{policies: verifierPolicies{}}, "/demo.api.v1.Demo/Read", nil)
+	ctx := newCallerContext(context.Background(), &Caller{checker: checker})
+
+	err := Check(ctx, "unknown")
+	require.True(t, errors.Is(err, ErrInternal))
+}
+
+func TestCheck(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `
+		policy "token" {
+			caveats {[
+				*allowed_method($0)
+					<-  method(#ambient, $0)
+					@   $0 in ["Method"]
+			]}
+		}
+	`))
+	i := iss.interceptor(t, WithVerifierPolicies(map[string]policy.Policy{"entity_owner": parsePolicy(t, `
+		policy "entity_owner" {
+			caveats {[
+				*owner($0)
+					<-  owner(#ambient, $0, $1),
+						caller(#ambient, $1)
+			]}
+		}
+	`)}))
+
+	fact := func(name string, ids ...biscuit.Atom) biscuit.Fact {
+		return biscuit.Fact{Predicate: biscuit.Predicate{Name: name, IDs: append([]biscuit.Atom{biscuit.Symbol("ambient")}, ids...)}}
+	}
+	testCases := []struct {
+		owner      string
+		authorized bool
+	}{
+		{owner: "userID", authorized: true},
+		{owner: "otherUserID"},
+	}
+	for _, testCase := range testCases {
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			caller, ok := CallerFromContext(ctx)
+			require.True(t, ok)
+			return nil, Check(ctx, "entity_owner",
+				fact("owner", biscuit.String("entity1"), biscuit.String(testCase.owner)),
+				fact("caller", biscuit.String(caller.UserID)),
+			)
+		}
+
+		ctx := iss.signedContext(t, token)
+		_, err := i.Unary(ctx, &prototesting.Object{Name: "entity1"}, &grpc.UnaryServerInfo{FullMethod: "/authorization.test.Service/Method"}, handler)
+		if testCase.authorized {
+			require.NoError(t, err, "owner %s", testCase.owner)
+			continue
+		}
+		require.True(t, errors.Is(err, ErrNotAuthorized), "owner %s", testCase.owner)
+	}
+}
+
+func TestCallCheckerSetFacts(t *testing.T) {
+	first := []biscuit.Fact{{Predicate: biscuit.Predicate{Name: "method", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String("Read")}}}}
+	second := []biscuit.Fact{{Predicate: biscuit.Predicate{Name: "method", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String("Update")}}}}
+
+	checker := newCallChecker(&grpcVerifier{}, "/demo.api.v1.Demo/Read", first)
+	require.Equal(t, first, checker.facts)
+
+	checker.setFacts(second)
+	require.Equal(t, second, checker.facts)
+}
@@ -18,11 +18,23 @@ type BiscuitClientInterceptor interface {
 
 type biscuitClientInterceptor struct {
 	rootPublicKey sig.PublicKey
+	rootKeyID     string
 	userKeyPair   *signedbiscuit.UserKeyPair
 	baseToken     []byte
 }
 
-func NewBiscuitClientInterceptor(rootPubBytes []byte, userPrivKeyBytes []byte, baseToken string) (BiscuitClientInterceptor, error) {
+// ClientInterceptorOption configures the interceptor created by NewBiscuitClientInterceptor.
+type ClientInterceptorOption func(*biscuitClientInterceptor)
+
+// WithRootKeyID sends the ID of the root key which signed the token in the MetadataRootKeyID metadata, so the
+// server verifies the token with this key only.
+func WithRootKeyID(id string) ClientInterceptorOption {
+	return func(i *biscuitClientInterceptor) {
+		i.rootKeyID = id
+	}
+}
+
+func NewBiscuitClientInterceptor(rootPubBytes []byte, userPrivKeyBytes []byte, baseToken string, opts ...ClientInterceptorOption) (BiscuitClientInterceptor, error) {
 	rootPubKey, err := sig.NewPublicKey(rootPubBytes)
 	if err != nil {
 		return nil, err
@@ -42,11 +54,15 @@ func NewBiscuitClientInterceptor(rootPubBytes []byte, userPrivKeyBytes []byte, b
 		return nil, err
 	}
 
-	return &biscuitClientInterceptor{
+	i := &biscuitClientInterceptor{
 		rootPublicKey: rootPubKey,
 		userKeyPair:   userKeypair,
 		baseToken:     decToken,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i, nil
 }
 
 func (i *biscuitClientInterceptor) Unary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
@@ -65,12 +81,22 @@ func (i *biscuitClientInterceptor) Stream(ctx context.Context, desc *grpc.Stream
 	return streamer(authorizedCtx, desc, cc, method, opts...)
 }
 
+// signToken returns ctx with the token signed by the user in its outgoing metadata, along with the root key ID
+// hint if set, replacing any previous value of these keys. The other metadata keys set by the caller are kept.
 func (i *biscuitClientInterceptor) signToken(ctx context.Context) (context.Context, error) {
 	signedToken, err := signedbiscuit.Sign(i.baseToken, i.rootPublicKey, i.userKeyPair)
 	if err != nil {
 		return nil, err
 	}
 
-	md := metadata.Pairs("authorization", base64.URLEncoding.EncodeToString(signedToken))
+	// FromOutgoingContext returns a copy of the metadata, safe to modify
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
+	}
+	md.Set(MetadataAuthorization, base64.URLEncoding.EncodeToString(signedToken))
+	if i.rootKeyID != "" {
+		md.Set(MetadataRootKeyID, i.rootKeyID)
+	}
 	return metadata.NewOutgoingContext(ctx, md), nil
 }
@@ -0,0 +1,102 @@
+package authorization
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestClientInterceptorRootKeyID(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `policy "empty" {}`))
+	userPrivKeyBytes, err := x509.MarshalECPrivateKey(iss.userPrivKey)
+	require.NoError(t, err)
+
+	keyring, err := NewRootKeyring(
+		RootKey{ID: "k1", PublicKey: newTestIssuer(t).rootKey.Public()},
+		RootKey{ID: "k2", PublicKey: iss.rootKey.Public()},
+	)
+	require.NoError(t, err)
+	server, err := NewBiscuitServerInterceptor(nil, WithAudience(testAudience, &iss.audienceKey.PublicKey), WithRootKeyring(keyring))
+	require.NoError(t, err)
+	info := &grpc.UnaryServerInfo{FullMethod: "/authorization.test.Service/Method"}
+
+	for _, keyID := range []string{"", "k2"} {
+		var opts []ClientInterceptorOption
+		if keyID != "" {
+			opts = append(opts, WithRootKeyID(keyID))
+		}
+		client, err := NewBiscuitClientInterceptor(iss.rootKey.Public().Bytes(), userPrivKeyBytes, base64.URLEncoding.EncodeToString(token), opts...)
+		require.NoError(t, err)
+
+		var md metadata.MD
+		err = client.Unary(context.Background(), info.FullMethod, nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, md[MetadataAuthorization], 1)
+		if keyID == "" {
+			require.Empty(t, md[MetadataRootKeyID])
+		} else {
+			require.Equal(t, []string{keyID}, md[MetadataRootKeyID])
+		}
+
+		_, err = server.Unary(metadata.NewIncomingContext(context.Background(), md), &prototesting.Object{}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			caller, ok := CallerFromContext(ctx)
+			require.True(t, ok)
+			require.Equal(t, "k2", caller.RootKeyID)
+			return nil, nil
+		})
+		require.NoError(t, err, "root key ID %q", keyID)
+	}
+}
+
+func TestClientInterceptorMetadata(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `
+		policy "tenant" {
+			caveats {[
+				*tenant($0)
+					<-  header(#ambient, "x-tenant-id", $0)
+					@   $0 in ["tenant1"]
+			]}
+		}
+	`))
+	userPrivKeyBytes, err := x509.MarshalECPrivateKey(iss.userPrivKey)
+	require.NoError(t, err)
+
+	client, err := NewBiscuitClientInterceptor(iss.rootKey.Public().Bytes(), userPrivKeyBytes, base64.URLEncoding.EncodeToString(token), WithRootKeyID("k1"))
+	require.NoError(t, err)
+	keyring, err := NewRootKeyring(RootKey{ID: "k1", PublicKey: iss.rootKey.Public()})
+	require.NoError(t, err)
+	server := iss.interceptor(t, WithRootKeyring(keyring), WithMetadataFacts(MetadataFactsConfig{Keys: []string{"x-tenant-id"}}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/authorization.test.Service/Method"}
+
+	// invoker forwards the outgoing metadata of the client to the server
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		require.Equal(t, []string{"k1"}, md[MetadataRootKeyID])
+		require.Len(t, md[MetadataAuthorization], 1, "the caller token is replaced")
+		_, err := server.Unary(metadata.NewIncomingContext(context.Background(), md), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &prototesting.Object{}, nil
+		})
+		return err
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "tenant1", MetadataAuthorization, "stale")
+	require.NoError(t, client.Unary(ctx, info.FullMethod, &prototesting.Object{}, nil, nil, invoker), "caller metadata become header facts")
+
+	ctx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-tenant-id", "tenant2"))
+	err = client.Unary(ctx, info.FullMethod, &prototesting.Object{}, nil, nil, invoker)
+	require.True(t, errors.Is(err, ErrNotAuthorized))
+}
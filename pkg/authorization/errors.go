@@ -0,0 +1,123 @@
+package authorization
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the google.rpc.ErrorInfo domain of the authorization errors.
+const ErrorDomain = "authorization.biscuit"
+
+// Reasons of the authorization errors, set on their google.rpc.ErrorInfo detail.
+const (
+	ReasonMissingToken     = "MISSING_TOKEN"
+	ReasonMalformedToken   = "MALFORMED_TOKEN"
+	ReasonInvalidSignature = "INVALID_SIGNATURE"
+	ReasonReplay           = "REPLAY"
+	ReasonNonceOutOfWindow = "NONCE_OUT_OF_WINDOW"
+	ReasonNotAuthorized    = "NOT_AUTHORIZED"
+	ReasonInvalidRequest   = "INVALID_REQUEST"
+	ReasonRequestTooLarge  = "REQUEST_TOO_LARGE"
+)
+
+var (
+	// ErrMissingToken is returned when the call doesn't hold any authorization metadata.
+	ErrMissingToken = &Error{Code: codes.Unauthenticated, Reason: ReasonMissingToken, Message: "missing authorization token"}
+	// ErrMalformedToken is returned when the token can't be decoded.
+	ErrMalformedToken = &Error{Code: codes.Unauthenticated, Reason: ReasonMalformedToken, Message: "malformed authorization token"}
+	// ErrInvalidSignature is returned when the token root, audience or user signatures are invalid.
+	ErrInvalidSignature = &Error{Code: codes.Unauthenticated, Reason: ReasonInvalidSignature, Message: "invalid authorization token signature"}
+	// ErrReplay is returned when the token signature nonce has already been used.
+	ErrReplay = &Error{Code: codes.Unauthenticated, Reason: ReasonReplay, Message: "authorization token replay attempt"}
+	// ErrNonceOutOfWindow is returned when the token signature timestamp is too far from the server time.
+	ErrNonceOutOfWindow = &Error{Code: codes.Unauthenticated, Reason: ReasonNonceOutOfWindow, Message: "authorization token signature out of time window"}
+	// ErrNotAuthorized is returned when the token policy denies the call.
+	ErrNotAuthorized = &Error{Code: codes.PermissionDenied, Reason: ReasonNotAuthorized, Message: "not authorized"}
+	// ErrInvalidRequest is returned when the request can't be converted to ambient facts.
+	ErrInvalidRequest = &Error{Code: codes.InvalidArgument, Reason: ReasonInvalidRequest, Message: "invalid request"}
+	// ErrRequestTooLarge is returned when the request exceeds the flatten limits, see FlattenLimits.
+	ErrRequestTooLarge = &Error{Code: codes.ResourceExhausted, Reason: ReasonRequestTooLarge, Message: "request too large to authorize"}
+	// ErrInternal is returned on server side failures. Its cause is never disclosed to the caller.
+	ErrInternal = &Error{Code: codes.Internal, Message: "authorization failure"}
+)
+
+// Error is an authorization failure. It converts to a gRPC status with its code and message, along with a
+// google.rpc.ErrorInfo detail holding its reason when the caller is allowed to know it.
+// Two errors are equal, for errors.Is, when they have the same code and reason.
+type Error struct {
+	Code    codes.Code
+	Reason  string
+	Message string
+	// Cause is the underlying error. It is only logged, and never sent to the caller.
+	Cause error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("authorization: %s: %v", e.Message, e.Cause)
+	}
+	return fmt.Sprintf("authorization: %s", e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code && t.Reason == e.Reason
+}
+
+// GRPCStatus implements the interface used by the grpc status package to convert errors to statuses.
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.Code, e.Message)
+	if e.Reason == "" {
+		return st
+	}
+
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: e.Reason,
+		Domain: ErrorDomain,
+	})
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// wrap returns a copy of the error, caused by err.
+func (e *Error) wrap(err error) *Error {
+	return &Error{
+		Code:    e.Code,
+		Reason:  e.Reason,
+		Message: e.Message,
+		Cause:   err,
+	}
+}
+
+// ErrorReason returns the reason of an authorization error received by a client, from its google.rpc.ErrorInfo
+// detail. It returns an empty string when the error doesn't hold any authorization reason.
+func ErrorReason(err error) string {
+	var authErr *Error
+	if errors.As(err, &authErr) {
+		return authErr.Reason
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+			return info.Reason
+		}
+	}
+	return ""
+}
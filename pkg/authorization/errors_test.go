@@ -0,0 +1,44 @@
+package authorization
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorGRPCStatus(t *testing.T) {
+	cause := errors.New("biscuit: verification failed")
+	err := ErrNotAuthorized.wrap(cause)
+
+	require.True(t, errors.Is(err, ErrNotAuthorized))
+	require.False(t, errors.Is(err, ErrReplay))
+	require.True(t, errors.Is(err, cause))
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.PermissionDenied, st.Code())
+	require.Equal(t, "not authorized", st.Message(), "the cause must not be sent to the caller")
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, ReasonNotAuthorized, info.Reason)
+	require.Equal(t, ErrorDomain, info.Domain)
+
+	st, ok = status.FromError(ErrInternal.wrap(cause))
+	require.True(t, ok)
+	require.Equal(t, codes.Internal, st.Code())
+	require.Empty(t, st.Details())
+}
+
+func TestErrorReason(t *testing.T) {
+	require.Equal(t, ReasonReplay, ErrorReason(ErrReplay.wrap(errors.New("replay"))))
+	require.Equal(t, ReasonMissingToken, ErrorReason(ErrMissingToken.GRPCStatus().Err()))
+	require.Equal(t, ReasonNonceOutOfWindow, ErrorReason(ErrNonceOutOfWindow.GRPCStatus().Err()))
+	require.Empty(t, ErrorReason(ErrInternal.GRPCStatus().Err()))
+	require.Empty(t, ErrorReason(status.Error(codes.Unavailable, "unavailable")))
+	require.Empty(t, ErrorReason(errors.New("not a status")))
+}
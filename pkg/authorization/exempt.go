@@ -0,0 +1,72 @@
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// exemptMethods matches the calls bypassing the biscuit verification.
+type exemptMethods struct {
+	methods  map[string]struct{}
+	services map[string]struct{}
+	prefixes []string
+}
+
+// newExemptMethods parses the exemption entries. An entry is either:
+//   - a full method name, i.e., /package.service/method,
+//   - a service full name, i.e., package.service, matching every method of the service,
+//   - a service name prefix ending with "*", i.e., grpc.reflection.*, matching every method of the services starting with it.
+//
+// Service names and prefixes can't hold a "/", as method names without their leading "/" would never match, and
+// a prefix can't be empty, as "*" alone would exempt every call.
+func newExemptMethods(entries []string) (*exemptMethods, error) {
+	e := &exemptMethods{
+		methods:  make(map[string]struct{}),
+		services: make(map[string]struct{}),
+	}
+
+	for _, entry := range entries {
+		switch {
+		case entry == "":
+			return nil, errors.New("authorization: empty exempt method")
+		case strings.HasPrefix(entry, "/"):
+			if _, _, err := splitFullMethod(entry); err != nil {
+				return nil, fmt.Errorf("authorization: invalid exempt method %q: %w", entry, err)
+			}
+			e.methods[entry] = struct{}{}
+		case strings.Contains(entry, "/"):
+			return nil, fmt.Errorf("authorization: invalid exempt method %q, full method names must start with a /", entry)
+		case entry == "*":
+			return nil, errors.New("authorization: exempt method prefix * would exempt every call")
+		case strings.HasSuffix(entry, "*"):
+			e.prefixes = append(e.prefixes, strings.TrimSuffix(entry, "*"))
+		default:
+			e.services[entry] = struct{}{}
+		}
+	}
+
+	return e, nil
+}
+
+// match returns true when the call to fullMethod is exempted from verification.
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (e *exemptMethods) match(fullMethod string) bool {
+	if _, ok := e.methods[fullMethod]; ok {
+		return true
+	}
+
+	service, _, err := splitFullMethod(fullMethod)
+	if err != nil {
+		return false
+	}
+	if _, ok := e.services[service]; ok {
+		return true
+	}
+	for _, prefix := range e.prefixes {
+		if strings.HasPrefix(service, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExemptMethodsMatch(t *testing.T) {
+	e, err := newExemptMethods([]string{
+		"/grpc.health.v1.Health/Check",
+		"grpc.reflection.*",
+		"demo.api.v1.Public",
+	})
+	require.NoError(t, err)
+
+	require.True(t, e.match("/grpc.health.v1.Health/Check"))
+	require.False(t, e.match("/grpc.health.v1.Health/Watch"))
+	require.True(t, e.match("/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"))
+	require.True(t, e.match("/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"))
+	require.True(t, e.match("/demo.api.v1.Public/Status"))
+	require.False(t, e.match("/demo.api.v1.PublicAPI/Status"))
+	require.False(t, e.match("/demo.api.v1.Demo/Status"))
+	require.False(t, e.match("invalid"))
+
+	_, err = newExemptMethods([]string{""})
+	require.Error(t, err)
+	_, err = newExemptMethods([]string{"/grpc.health.v1.Health"})
+	require.Error(t, err)
+	_, err = newExemptMethods([]string{"grpc.health.v1.Health/Check"})
+	require.Error(t, err, "full method names start with a /")
+	_, err = newExemptMethods([]string{"grpc.health.v1/*"})
+	require.Error(t, err, "prefixes are service names")
+	_, err = newExemptMethods([]string{"*"})
+	require.Error(t, err, "every call can't be exempted")
+}
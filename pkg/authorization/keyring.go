@@ -0,0 +1,196 @@
+package authorization
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/flynn/biscuit-go/sig"
+)
+
+// MetadataRootKeyID is the metadata key of the root key ID hint.
+const MetadataRootKeyID = "authorization-key-id"
+
+// RootKeyIDFact is the name of the authority fact holding the root key ID of a token, i.e.,
+// root_key_id(#authority, "keyID").
+const RootKeyIDFact = "root_key_id"
+
+// RootKey is a root public key, valid from NotBefore until NotAfter. A zero NotBefore or NotAfter leaves the
+// validity window open on this side.
+type RootKey struct {
+	ID        string
+	PublicKey sig.PublicKey
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+func (k RootKey) validAt(t time.Time) bool {
+	if !k.NotBefore.IsZero() && t.Before(k.NotBefore) {
+		return false
+	}
+	if !k.NotAfter.IsZero() && !t.Before(k.NotAfter) {
+		return false
+	}
+	return true
+}
+
+// RootKeyring holds the root public keys accepted by the server interceptor, allowing root key rotations
+// without invalidating the tokens signed by the previous keys.
+type RootKeyring struct {
+	keys []RootKey
+	byID map[string]RootKey
+}
+
+// NewRootKeyring creates a keyring from keys, whose IDs must be unique.
+func NewRootKeyring(keys ...RootKey) (*RootKeyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("authorization: at least one root key is required")
+	}
+
+	byID := make(map[string]RootKey, len(keys))
+	for _, k := range keys {
+		if _, exists := byID[k.ID]; exists {
+			return nil, fmt.Errorf("authorization: duplicate root key ID %q", k.ID)
+		}
+		if !k.NotAfter.IsZero() && !k.NotAfter.After(k.NotBefore) {
+			return nil, fmt.Errorf("authorization: root key %q expires before being valid", k.ID)
+		}
+		byID[k.ID] = k
+	}
+
+	return &RootKeyring{
+		keys: keys,
+		byID: byID,
+	}, nil
+}
+
+// RootKeyringEntry is the serialized form of a RootKey in a keyring file, as written by cmd/keys.
+type RootKeyringEntry struct {
+	ID        string    `json:"id"`
+	PublicKey []byte    `json:"public_key"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+// ParseRootKeyring parses a JSON keyring file, holding a list of RootKeyringEntry.
+func ParseRootKeyring(data []byte) (*RootKeyring, error) {
+	var entries []RootKeyringEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	keys := make([]RootKey, 0, len(entries))
+	for _, e := range entries {
+		pubkey, err := sig.NewPublicKey(e.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("authorization: invalid root key %q: %w", e.ID, err)
+		}
+		keys = append(keys, RootKey{
+			ID:        e.ID,
+			PublicKey: pubkey,
+			NotBefore: e.NotBefore,
+			NotAfter:  e.NotAfter,
+		})
+	}
+
+	return NewRootKeyring(keys...)
+}
+
+// verifyToken verifies the signatures of b with the root key, and returns a new verifier on its facts.
+var verifyToken = func(b *biscuit.Biscuit, rootKey sig.PublicKey) (biscuit.Verifier, error) {
+	return b.Verify(rootKey)
+}
+
+// selectKey returns the root key of the token valid at now, along with the verifier of the token verified with
+// this key. When the key ID hint is set, only this key is tried. Otherwise, when the token holds a root_key_id
+// authority fact, read before verifying the token, only the key with this ID is tried, and else every valid key
+// is. The root_key_id authority fact, if any, must match the key ID once the token is verified with it.
+func (r *RootKeyring) selectKey(b *biscuit.Biscuit, hint string, now time.Time) (RootKey, biscuit.Verifier, error) {
+	if hint == "" {
+		keyID, err := unverifiedRootKeyID(b)
+		if err != nil {
+			return RootKey{}, nil, err
+		}
+		hint = keyID
+	}
+
+	candidates := r.keys
+	if hint != "" {
+		k, ok := r.byID[hint]
+		if !ok {
+			return RootKey{}, nil, fmt.Errorf("unknown root key ID %q", hint)
+		}
+		candidates = []RootKey{k}
+	}
+
+	for _, k := range candidates {
+		if !k.validAt(now) {
+			continue
+		}
+
+		verifier, err := verifyToken(b, k.PublicKey)
+		if err != nil {
+			continue
+		}
+
+		keyIDs, err := queryRootKeyIDs(verifier)
+		if err != nil {
+			return RootKey{}, nil, err
+		}
+		for _, keyID := range keyIDs {
+			if keyID != k.ID {
+				return RootKey{}, nil, fmt.Errorf("token root key ID %q doesn't match root key %q", keyID, k.ID)
+			}
+		}
+
+		return k, verifier, nil
+	}
+
+	return RootKey{}, nil, errors.New("no valid root key found for the token")
+}
+
+// unverifiedRootKeyID returns the root key ID held by the root_key_id authority fact of the token, without
+// verifying it, or an empty ID when the token holds none.
+func unverifiedRootKeyID(b *biscuit.Biscuit) (string, error) {
+	verifier, err := biscuit.NewVerifier(b)
+	if err != nil {
+		return "", err
+	}
+
+	keyIDs, err := queryRootKeyIDs(verifier)
+	if err != nil || len(keyIDs) == 0 {
+		return "", err
+	}
+	if len(keyIDs) > 1 {
+		return "", fmt.Errorf("token holds several root key IDs %q", keyIDs)
+	}
+	return keyIDs[0], nil
+}
+
+// queryRootKeyIDs returns the root key IDs held by the root_key_id authority facts of the token.
+func queryRootKeyIDs(verifier biscuit.Verifier) ([]string, error) {
+	facts, err := verifier.Query(biscuit.Rule{
+		Head: biscuit.Predicate{Name: RootKeyIDFact, IDs: []biscuit.Atom{biscuit.Variable("0")}},
+		Body: []biscuit.Predicate{
+			{Name: RootKeyIDFact, IDs: []biscuit.Atom{biscuit.Symbol("authority"), biscuit.Variable("0")}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keyIDs := make([]string, 0, len(facts))
+	for _, f := range facts {
+		if len(f.IDs) != 1 {
+			return nil, fmt.Errorf("invalid root key ID fact %s", f)
+		}
+		keyID, ok := f.IDs[0].(biscuit.String)
+		if !ok {
+			return nil, fmt.Errorf("invalid root key ID fact %s", f)
+		}
+		keyIDs = append(keyIDs, string(keyID))
+	}
+	return keyIDs, nil
+}
@@ -0,0 +1,123 @@
+package authorization
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/flynn/biscuit-go/sig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRootKeyring(t *testing.T) {
+	pubkey := sig.GenerateKeypair(rand.Reader).Public()
+	now := time.Now()
+
+	_, err := NewRootKeyring()
+	require.Error(t, err, "a key is required")
+
+	_, err = NewRootKeyring(RootKey{ID: "k1", PublicKey: pubkey}, RootKey{ID: "k1", PublicKey: pubkey})
+	require.Error(t, err, "key IDs must be unique")
+
+	_, err = NewRootKeyring(RootKey{ID: "k1", PublicKey: pubkey, NotBefore: now, NotAfter: now.Add(-time.Hour)})
+	require.Error(t, err, "keys must be valid before expiring")
+
+	keyring, err := NewRootKeyring(RootKey{ID: "k1", PublicKey: pubkey}, RootKey{ID: "k2", PublicKey: pubkey})
+	require.NoError(t, err)
+	require.Len(t, keyring.keys, 2)
+}
+
+func TestRootKeyValidAt(t *testing.T) {
+	now := time.Now()
+
+	require.True(t, RootKey{}.validAt(now), "an unbounded key is always valid")
+
+	k := RootKey{NotBefore: now, NotAfter: now.Add(time.Hour)}
+	require.False(t, k.validAt(now.Add(-time.Second)))
+	require.True(t, k.validAt(now))
+	require.True(t, k.validAt(now.Add(time.Hour-time.Second)))
+	require.False(t, k.validAt(now.Add(time.Hour)))
+}
+
+func TestParseRootKeyring(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	pubkey1 := sig.GenerateKeypair(rand.Reader).Public()
+	pubkey2 := sig.GenerateKeypair(rand.Reader).Public()
+
+	data, err := json.Marshal([]RootKeyringEntry{
+		{ID: "k1", PublicKey: pubkey1.Bytes(), NotAfter: now.Add(time.Hour)},
+		{ID: "k2", PublicKey: pubkey2.Bytes(), NotBefore: now},
+	})
+	require.NoError(t, err)
+
+	keyring, err := ParseRootKeyring(data)
+	require.NoError(t, err)
+	require.Len(t, keyring.keys, 2)
+
+	require.Equal(t, pubkey1.Bytes(), keyring.byID["k1"].PublicKey.Bytes())
+	require.True(t, keyring.byID["k1"].NotBefore.IsZero())
+	require.True(t, keyring.byID["k1"].NotAfter.Equal(now.Add(time.Hour)))
+
+	require.Equal(t, pubkey2.Bytes(), keyring.byID["k2"].PublicKey.Bytes())
+	require.True(t, keyring.byID["k2"].NotBefore.Equal(now))
+	require.True(t, keyring.byID["k2"].NotAfter.IsZero())
+
+	_, err = ParseRootKeyring([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestRootKeyringSelectKey(t *testing.T) {
+	now := time.Now()
+	iss1, iss2, iss3 := newTestIssuer(t), newTestIssuer(t), newTestIssuer(t)
+	keyring, err := NewRootKeyring(
+		RootKey{ID: "k1", PublicKey: iss1.rootKey.Public()},
+		RootKey{ID: "k2", PublicKey: iss2.rootKey.Public()},
+		RootKey{ID: "expired", PublicKey: iss3.rootKey.Public(), NotAfter: now.Add(-time.Minute)},
+	)
+	require.NoError(t, err)
+
+	token := func(iss *testIssuer, keyID string) *biscuit.Biscuit {
+		var facts []biscuit.Fact
+		if keyID != "" {
+			facts = append(facts, biscuit.Fact{Predicate: biscuit.Predicate{
+				Name: RootKeyIDFact,
+				IDs:  []biscuit.Atom{biscuit.Symbol("authority"), biscuit.String(keyID)},
+			}})
+		}
+		b, err := biscuit.Unmarshal(iss.sign(t, iss.baseToken(t, now.Add(time.Hour), parsePolicy(t, `policy "empty" {}`), facts...)))
+		require.NoError(t, err)
+		return b
+	}
+
+	testCases := []struct {
+		name  string
+		token *biscuit.Biscuit
+		hint  string
+		key   string
+	}{
+		{name: "fallback to the second key", token: token(iss2, ""), key: "k2"},
+		{name: "hint", token: token(iss2, ""), hint: "k2", key: "k2"},
+		{name: "wrong hint", token: token(iss2, ""), hint: "k1"},
+		{name: "unknown hint", token: token(iss2, ""), hint: "k4"},
+		{name: "expired key", token: token(iss3, "")},
+		{name: "expired key hint", token: token(iss3, ""), hint: "expired"},
+		{name: "root key ID fact", token: token(iss2, "k2"), key: "k2"},
+		{name: "root key ID fact and hint", token: token(iss2, "k2"), hint: "k2", key: "k2"},
+		{name: "mismatching root key ID fact", token: token(iss1, "k2")},
+		{name: "mismatching root key ID fact and hint", token: token(iss1, "k2"), hint: "k1"},
+		{name: "unknown root key ID fact", token: token(iss1, "k4")},
+		{name: "unknown key", token: token(newTestIssuer(t), "")},
+	}
+	for _, testCase := range testCases {
+		k, verifier, err := keyring.selectKey(testCase.token, testCase.hint, now)
+		if testCase.key == "" {
+			require.Error(t, err, testCase.name)
+			continue
+		}
+		require.NoError(t, err, testCase.name)
+		require.Equal(t, testCase.key, k.ID, testCase.name)
+		require.NotNil(t, verifier, testCase.name)
+	}
+}
@@ -0,0 +1,37 @@
+package authorization
+
+import (
+	"errors"
+	"expvar"
+
+	"demo/pkg/protofacts"
+)
+
+// FlattenLimits bounds the facts flattened from a message, so a large request can't make the verifier do an
+// unbounded amount of work before denying it, see protofacts.Limits.
+type FlattenLimits = protofacts.Limits
+
+// DefaultFlattenLimits are the limits of the server interceptor, unless set with WithFlattenLimits.
+// Large binary fields, such as file contents, should rather be skipped with the authz proto options.
+var DefaultFlattenLimits = FlattenLimits{
+	MaxDepth:     32,
+	MaxFacts:     10000,
+	MaxAtomBytes: 64 << 10,
+}
+
+// FlattenLimitsExceededMetric is the name of the expvar map counting the messages rejected for exceeding
+// the flatten limits, by limit: "depth", "facts" or "atom_bytes".
+const FlattenLimitsExceededMetric = "authorization_flatten_limits_exceeded"
+
+var flattenLimitsExceeded = expvar.NewMap(FlattenLimitsExceededMetric)
+
+// countLimitError records err in the FlattenLimitsExceededMetric map, when it is a *protofacts.LimitError.
+// It returns whether it is one.
+func countLimitError(err error) bool {
+	var limitErr *protofacts.LimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	flattenLimitsExceeded.Add(limitErr.Limit, 1)
+	return true
+}
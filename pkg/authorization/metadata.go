@@ -0,0 +1,109 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flynn/biscuit-go"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DefaultMaxMetadataValues is the maximum number of values of a metadata key converted to facts, unless set
+	// in MetadataFactsConfig.
+	DefaultMaxMetadataValues = 8
+	// DefaultMaxMetadataValueBytes is the maximum size of a metadata value converted to facts, unless set
+	// in MetadataFactsConfig.
+	DefaultMaxMetadataValueBytes = 1 << 10
+)
+
+// binaryMetadataSuffix ends the keys of the binary metadata, whose values can be any bytes.
+const binaryMetadataSuffix = "-bin"
+
+// MetadataFactsConfig configures the conversion of the incoming metadata to header(#ambient, key, value) facts.
+type MetadataFactsConfig struct {
+	// Keys is the allowlist of the metadata keys converted to facts, i.e., x-tenant-id. Keys are case insensitive.
+	Keys []string
+	// MaxValues is the maximum number of values of a key. Calls sending more are rejected with ErrRequestTooLarge.
+	// Defaults to DefaultMaxMetadataValues.
+	MaxValues int
+	// MaxValueBytes is the maximum size of a value. Calls sending a larger one are rejected with
+	// ErrRequestTooLarge. Defaults to DefaultMaxMetadataValueBytes.
+	MaxValueBytes int
+	// AllowExcluded allows the keys excluded by default in Keys: the authorization and authorization-key-id keys
+	// of the token, and the binary keys ending with -bin, whose values are converted to bytes.
+	AllowExcluded bool
+}
+
+func (c MetadataFactsConfig) validate() error {
+	if c.MaxValues < 0 {
+		return errors.New("authorization: metadata max values must not be negative")
+	}
+	if c.MaxValueBytes < 0 {
+		return errors.New("authorization: metadata max value bytes must not be negative")
+	}
+	if c.AllowExcluded {
+		return nil
+	}
+	for _, key := range c.Keys {
+		if isExcludedMetadata(strings.ToLower(key)) {
+			return fmt.Errorf("authorization: metadata key %q is excluded from facts, unless AllowExcluded is set", key)
+		}
+	}
+	return nil
+}
+
+// isExcludedMetadata returns whether the metadata key is excluded from facts by default.
+func isExcludedMetadata(key string) bool {
+	return key == MetadataAuthorization || key == MetadataRootKeyID || strings.HasSuffix(key, binaryMetadataSuffix)
+}
+
+// MetadataFactProvider provides the header(#ambient, key, value) facts of the incoming metadata keys allowed by
+// cfg, one for each of their values, i.e., header(#ambient, "x-tenant-id", "tenant1"). Keys are given in lower case
+// in the facts, and missing keys have no facts. A policy can then check the tenant header against the token with
+// header(#ambient, "x-tenant-id", $0), tenant(#authority, $0).
+func MetadataFactProvider(cfg MetadataFactsConfig) (FactProvider, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.MaxValues == 0 {
+		cfg.MaxValues = DefaultMaxMetadataValues
+	}
+	if cfg.MaxValueBytes == 0 {
+		cfg.MaxValueBytes = DefaultMaxMetadataValueBytes
+	}
+
+	keys := make([]string, 0, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		keys = append(keys, strings.ToLower(key))
+	}
+
+	return FactProviderFunc(func(ctx context.Context, _ string, _ interface{}) ([]biscuit.Fact, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, nil
+		}
+
+		var facts []biscuit.Fact
+		for _, key := range keys {
+			values := md[key]
+			if len(values) > cfg.MaxValues {
+				return nil, ErrRequestTooLarge.wrap(fmt.Errorf("metadata %q holds %d values, over %d", key, len(values), cfg.MaxValues))
+			}
+			for _, value := range values {
+				if len(value) > cfg.MaxValueBytes {
+					return nil, ErrRequestTooLarge.wrap(fmt.Errorf("metadata %q holds a %d bytes value, over %d", key, len(value), cfg.MaxValueBytes))
+				}
+
+				var atom biscuit.Atom = biscuit.String(value)
+				if strings.HasSuffix(key, binaryMetadataSuffix) {
+					atom = biscuit.Bytes(value)
+				}
+				facts = append(facts, ambientFact("header", biscuit.String(key), atom))
+			}
+		}
+		return facts, nil
+	}), nil
+}
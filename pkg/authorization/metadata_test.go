@@ -0,0 +1,93 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetadataFactProvider(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "token",
+		"x-tenant-id", "tenant1",
+		"x-request-reason", "incident",
+		"x-request-reason", "audit",
+		"x-other", "other",
+		"x-trace-bin", "\x00\x01",
+	))
+
+	provider, err := MetadataFactProvider(MetadataFactsConfig{Keys: []string{"X-Tenant-ID", "x-request-reason", "x-missing"}})
+	require.NoError(t, err)
+	facts, err := provider.Facts(ctx, "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Equal(t, []biscuit.Fact{
+		ambientFact("header", biscuit.String("x-tenant-id"), biscuit.String("tenant1")),
+		ambientFact("header", biscuit.String("x-request-reason"), biscuit.String("incident")),
+		ambientFact("header", biscuit.String("x-request-reason"), biscuit.String("audit")),
+	}, facts)
+
+	facts, err = provider.Facts(context.Background(), "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Empty(t, facts, "calls without metadata have no facts")
+
+	provider, err = MetadataFactProvider(MetadataFactsConfig{Keys: []string{"x-trace-bin"}, AllowExcluded: true})
+	require.NoError(t, err)
+	facts, err = provider.Facts(ctx, "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Equal(t, []biscuit.Fact{
+		ambientFact("header", biscuit.String("x-trace-bin"), biscuit.Bytes("\x00\x01")),
+	}, facts, "binary values are converted to bytes")
+}
+
+func TestMetadataFactProviderConfig(t *testing.T) {
+	for _, key := range []string{"authorization", "Authorization", "authorization-key-id", "x-trace-bin"} {
+		_, err := MetadataFactProvider(MetadataFactsConfig{Keys: []string{key}})
+		require.Error(t, err, "%s is excluded by default", key)
+
+		_, err = MetadataFactProvider(MetadataFactsConfig{Keys: []string{key}, AllowExcluded: true})
+		require.NoError(t, err)
+	}
+
+	_, err := MetadataFactProvider(MetadataFactsConfig{Keys: []string{"x-tenant-id"}, MaxValues: -1})
+	require.Error(t, err)
+	_, err = MetadataFactProvider(MetadataFactsConfig{Keys: []string{"x-tenant-id"}, MaxValueBytes: -1})
+	require.Error(t, err)
+}
+
+func TestMetadataFactProviderLimits(t *testing.T) {
+	testCases := []struct {
+		cfg      MetadataFactsConfig
+		values   []string
+		tooLarge bool
+	}{
+		{cfg: MetadataFactsConfig{MaxValues: 2}, values: []string{"a", "b"}},
+		{cfg: MetadataFactsConfig{MaxValues: 2}, values: []string{"a", "b", "c"}, tooLarge: true},
+		{cfg: MetadataFactsConfig{MaxValueBytes: 4}, values: []string{"abcd"}},
+		{cfg: MetadataFactsConfig{MaxValueBytes: 4}, values: []string{"abcde"}, tooLarge: true},
+		{values: make([]string, DefaultMaxMetadataValues)},
+		{values: make([]string, DefaultMaxMetadataValues+1), tooLarge: true},
+		{values: []string{strings.Repeat("a", DefaultMaxMetadataValueBytes+1)}, tooLarge: true},
+	}
+	for _, testCase := range testCases {
+		testCase.cfg.Keys = []string{"x-request-reason"}
+		provider, err := MetadataFactProvider(testCase.cfg)
+		require.NoError(t, err)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{"x-request-reason": testCase.values})
+		facts, err := provider.Facts(ctx, "/demo.api.v1.Demo/Read", nil)
+		if !testCase.tooLarge {
+			require.NoError(t, err, "config %+v", testCase.cfg)
+			require.Len(t, facts, len(testCase.values))
+			continue
+		}
+		require.True(t, errors.Is(err, ErrRequestTooLarge), "config %+v", testCase.cfg)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	}
+}
@@ -0,0 +1,162 @@
+package authorization
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// FactProvider adds ambient facts to the verifier of every call, next to the service, method and arg facts.
+type FactProvider interface {
+	// Facts returns the facts of a call to fullMethod, with req. ctx is the call context, and req is nil when
+	// a stream is opened, before any message is received. Returning an error denies the call, with the error
+	// when it is an *Error, and ErrInternal otherwise.
+	// fullMethod is the full RPC method string, i.e., /package.service/method.
+	Facts(ctx context.Context, fullMethod string, req interface{}) ([]biscuit.Fact, error)
+}
+
+// FactProviderFunc is a function implementing FactProvider.
+type FactProviderFunc func(ctx context.Context, fullMethod string, req interface{}) ([]biscuit.Fact, error)
+
+// Facts calls f.
+func (f FactProviderFunc) Facts(ctx context.Context, fullMethod string, req interface{}) ([]biscuit.Fact, error) {
+	return f(ctx, fullMethod, req)
+}
+
+// TimeFactProvider provides the time(#ambient, date) fact of the current time, given by now, along with the
+// weekday(#ambient, day) and hour(#ambient, hour) facts of this time in loc, i.e., weekday(#ambient, "Monday") and
+// hour(#ambient, 14), so policies can restrict calls to the business hours. loc defaults to UTC when nil.
+func TimeFactProvider(now func() time.Time, loc *time.Location) FactProvider {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return FactProviderFunc(func(context.Context, string, interface{}) ([]biscuit.Fact, error) {
+		t := now()
+		local := t.In(loc)
+		return []biscuit.Fact{
+			ambientFact("time", biscuit.Date(t)),
+			ambientFact("weekday", biscuit.String(local.Weekday().String())),
+			ambientFact("hour", biscuit.Integer(local.Hour())),
+		}, nil
+	})
+}
+
+// DeadlineFactProvider provides the deadline(#ambient, date) fact of the call deadline, and the
+// timeout(#ambient, milliseconds) fact of the time left until then, from the current time given by now.
+// Calls without a deadline have no facts.
+func DeadlineFactProvider(now func() time.Time) FactProvider {
+	return FactProviderFunc(func(ctx context.Context, _ string, _ interface{}) ([]biscuit.Fact, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return nil, nil
+		}
+		return []biscuit.Fact{
+			ambientFact("deadline", biscuit.Date(deadline)),
+			ambientFact("timeout", biscuit.Integer(deadline.Sub(now()).Milliseconds())),
+		}, nil
+	})
+}
+
+// PeerFactProvider provides the peer_address(#ambient, "ip") fact of the IP address of the client, and the
+// peer_network(#ambient, name) facts of the networks holding it. networks maps the network names to their CIDR,
+// i.e., {"office": "192.0.2.0/24"}, so a policy can restrict calls to the office network with
+// peer_network(#ambient, "office"). Calls from a client without an IP address have no facts.
+func PeerFactProvider(networks map[string]string) (FactProvider, error) {
+	nets := make(map[string]*net.IPNet, len(networks))
+	for name, cidr := range networks {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("authorization: invalid %q network: %w", name, err)
+		}
+		nets[name] = ipNet
+	}
+
+	return FactProviderFunc(func(ctx context.Context, _ string, _ interface{}) ([]biscuit.Fact, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok || p.Addr == nil {
+			return nil, nil
+		}
+		ip := peerIP(p.Addr)
+		if ip == nil {
+			return nil, nil
+		}
+
+		facts := []biscuit.Fact{ambientFact("peer_address", biscuit.String(ip.String()))}
+		for name, ipNet := range nets {
+			if ipNet.Contains(ip) {
+				facts = append(facts, ambientFact("peer_network", biscuit.String(name)))
+			}
+		}
+		return facts, nil
+	}), nil
+}
+
+// peerIP returns the IP address of addr, or nil when it doesn't have one, i.e., on unix sockets.
+func peerIP(addr net.Addr) net.IP {
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		return addr.IP
+	case *net.UDPAddr:
+		return addr.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+// TLSFactProvider provides the tls_client_subject(#ambient, subject) fact of the client certificate subject,
+// i.e., "CN=client,O=Demo", and its tls_client_san(#ambient, kind, value) facts, one for each subject alternative
+// name, kind being one of "dns", "email", "ip" or "uri". A policy can then require a client certificate with
+// tls_client_subject(#ambient, $0). Only the certificates verified by the server TLS config have facts, so it
+// must set a ClientAuth verifying them, i.e., tls.VerifyClientCertIfGiven.
+func TLSFactProvider() FactProvider {
+	return FactProviderFunc(func(ctx context.Context, _ string, _ interface{}) ([]biscuit.Fact, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return nil, nil
+		}
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+			return nil, nil
+		}
+		return certificateFacts(tlsInfo.State.VerifiedChains[0][0]), nil
+	})
+}
+
+// certificateFacts returns the subject and subject alternative name facts of cert.
+func certificateFacts(cert *x509.Certificate) []biscuit.Fact {
+	san := func(kind, value string) biscuit.Fact {
+		return ambientFact("tls_client_san", biscuit.String(kind), biscuit.String(value))
+	}
+
+	facts := []biscuit.Fact{ambientFact("tls_client_subject", biscuit.String(cert.Subject.String()))}
+	for _, name := range cert.DNSNames {
+		facts = append(facts, san("dns", name))
+	}
+	for _, email := range cert.EmailAddresses {
+		facts = append(facts, san("email", email))
+	}
+	for _, ip := range cert.IPAddresses {
+		facts = append(facts, san("ip", ip.String()))
+	}
+	for _, uri := range cert.URIs {
+		facts = append(facts, san("uri", uri.String()))
+	}
+	return facts
+}
+
+// ambientFact returns the name(#ambient, ids...) fact.
+func ambientFact(name string, ids ...biscuit.Atom) biscuit.Fact {
+	return biscuit.Fact{Predicate: biscuit.Predicate{
+		Name: name,
+		IDs:  append([]biscuit.Atom{biscuit.Symbol("ambient")}, ids...),
+	}}
+}
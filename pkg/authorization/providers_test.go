@@ -0,0 +1,204 @@
+package authorization
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/flynn/biscuit-go/sig"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestTimeAndDeadlineFactProviders(t *testing.T) {
+	now := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	facts, err := TimeFactProvider(clock, nil).Facts(context.Background(), "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Equal(t, []biscuit.Fact{
+		ambientFact("time", biscuit.Date(now)),
+		ambientFact("weekday", biscuit.String("Thursday")),
+		ambientFact("hour", biscuit.Integer(10)),
+	}, facts)
+
+	// 10:00 UTC is 05:00 on Thursday in UTC-5, and 00:00 on Friday in UTC+14
+	for _, testCase := range []struct {
+		loc     *time.Location
+		weekday string
+		hour    int64
+	}{
+		{loc: time.FixedZone("UTC-5", -5*3600), weekday: "Thursday", hour: 5},
+		{loc: time.FixedZone("UTC+14", 14*3600), weekday: "Friday", hour: 0},
+	} {
+		facts, err := TimeFactProvider(clock, testCase.loc).Facts(context.Background(), "/demo.api.v1.Demo/Read", nil)
+		require.NoError(t, err)
+		require.Equal(t, []biscuit.Fact{
+			ambientFact("time", biscuit.Date(now)),
+			ambientFact("weekday", biscuit.String(testCase.weekday)),
+			ambientFact("hour", biscuit.Integer(testCase.hour)),
+		}, facts, testCase.loc.String())
+	}
+
+	facts, err = DeadlineFactProvider(clock).Facts(context.Background(), "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Empty(t, facts, "calls without deadline have no facts")
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(1500*time.Millisecond))
+	defer cancel()
+	facts, err = DeadlineFactProvider(clock).Facts(ctx, "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Equal(t, []biscuit.Fact{
+		ambientFact("deadline", biscuit.Date(now.Add(1500*time.Millisecond))),
+		ambientFact("timeout", biscuit.Integer(1500)),
+	}, facts)
+}
+
+func TestPeerFactProvider(t *testing.T) {
+	_, err := PeerFactProvider(map[string]string{"office": "192.0.2.0"})
+	require.Error(t, err, "networks must be CIDRs")
+
+	provider, err := PeerFactProvider(map[string]string{
+		"office": "192.0.2.0/24",
+		"vpn":    "2001:db8::/32",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		addr  net.Addr
+		facts []biscuit.Fact
+	}{
+		{
+			addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 4242},
+			facts: []biscuit.Fact{
+				ambientFact("peer_address", biscuit.String("192.0.2.10")),
+				ambientFact("peer_network", biscuit.String("office")),
+			},
+		},
+		{
+			addr: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 4242},
+			facts: []biscuit.Fact{
+				ambientFact("peer_address", biscuit.String("2001:db8::1")),
+				ambientFact("peer_network", biscuit.String("vpn")),
+			},
+		},
+		{
+			addr:  &net.TCPAddr{IP: net.ParseIP("198.51.100.1"), Port: 4242},
+			facts: []biscuit.Fact{ambientFact("peer_address", biscuit.String("198.51.100.1"))},
+		},
+		{
+			addr: &net.UnixAddr{Name: "/tmp/demo.sock", Net: "unix"},
+		},
+	}
+	for _, testCase := range testCases {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: testCase.addr})
+		facts, err := provider.Facts(ctx, "/demo.api.v1.Demo/Read", nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, testCase.facts, facts, "peer %s", testCase.addr)
+	}
+
+	facts, err := provider.Facts(context.Background(), "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Empty(t, facts, "calls without peer have no facts")
+}
+
+func TestTLSFactProvider(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "client", Organization: []string{"Demo"}},
+		DNSNames:       []string{"client.demo.local"},
+		EmailAddresses: []string{"client@demo.local"},
+		IPAddresses:    []net.IP{net.ParseIP("192.0.2.10")},
+		URIs:           []*url.URL{{Scheme: "spiffe", Host: "demo.local", Path: "/client"}},
+	}
+
+	verified := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}, VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+	facts, err := TLSFactProvider().Facts(verified, "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Equal(t, []biscuit.Fact{
+		ambientFact("tls_client_subject", biscuit.String("CN=client,O=Demo")),
+		ambientFact("tls_client_san", biscuit.String("dns"), biscuit.String("client.demo.local")),
+		ambientFact("tls_client_san", biscuit.String("email"), biscuit.String("client@demo.local")),
+		ambientFact("tls_client_san", biscuit.String("ip"), biscuit.String("192.0.2.10")),
+		ambientFact("tls_client_san", biscuit.String("uri"), biscuit.String("spiffe://demo.local/client")),
+	}, facts)
+
+	unverified := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+	}})
+	facts, err = TLSFactProvider().Facts(unverified, "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Empty(t, facts, "unverified certificates have no facts")
+}
+
+func TestGrpcVerifierFactProviders(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	req := &prototesting.Object{Name: "obj1"}
+
+	v := &grpcVerifier{
+		logger: zap.NewNop(),
+		providers: []FactProvider{FactProviderFunc(func(gotCtx context.Context, fullMethod string, gotReq interface{}) ([]biscuit.Fact, error) {
+			require.Equal(t, "value", gotCtx.Value(ctxKey{}))
+			require.Equal(t, "/authorization.test.Service/Method", fullMethod)
+			require.Equal(t, req, gotReq)
+			return []biscuit.Fact{ambientFact("provided")}, nil
+		})},
+	}
+	facts, err := v.ambientFacts(ctx, "/authorization.test.Service/Method", req)
+	require.NoError(t, err)
+	require.Equal(t, ambientFact("provided"), facts[len(facts)-1], "provided facts follow the request facts")
+
+	v.providers = []FactProvider{FactProviderFunc(func(context.Context, string, interface{}) ([]biscuit.Fact, error) {
+		return nil, errors.New("provider failure")
+	})}
+	_, err = v.ambientFacts(ctx, "/authorization.test.Service/Method", req)
+	require.True(t, errors.Is(err, ErrInternal))
+
+	v.providers = []FactProvider{FactProviderFunc(func(context.Context, string, interface{}) ([]biscuit.Fact, error) {
+		return nil, ErrNotAuthorized.wrap(errors.New("denied by provider"))
+	})}
+	_, err = v.ambientFacts(ctx, "/authorization.test.Service/Method", req)
+	require.True(t, errors.Is(err, ErrNotAuthorized), "authorization errors are returned as is")
+}
+
+func TestWithTimeFacts(t *testing.T) {
+	rootPubKey := sig.GenerateKeypair(rand.Reader).Public().Bytes()
+	audienceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Date(2021, 3, 6, 23, 30, 0, 0, time.UTC)
+	paris := time.FixedZone("CET", 3600)
+	i, err := NewBiscuitServerInterceptor(rootPubKey,
+		WithAudience("http://audience.local", &audienceKey.PublicKey),
+		WithTimeFacts(paris),
+		WithClock(func() time.Time { return now }),
+		WithMetadataFacts(MetadataFactsConfig{Keys: []string{"x-tenant-id"}}),
+	)
+	require.NoError(t, err)
+
+	providers := i.(*biscuitServerInterceptor).providers
+	require.Len(t, providers, 2, "time and metadata providers")
+	facts, err := providers[0].Facts(context.Background(), "/demo.api.v1.Demo/Read", nil)
+	require.NoError(t, err)
+	require.Equal(t, []biscuit.Fact{
+		ambientFact("time", biscuit.Date(now)),
+		ambientFact("weekday", biscuit.String("Sunday")),
+		ambientFact("hour", biscuit.Integer(0)),
+	}, facts, "the time facts use the interceptor clock, whatever the options order")
+}
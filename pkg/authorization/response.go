@@ -0,0 +1,123 @@
+package authorization
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flynn/biscuit-go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"demo/pkg/protofacts"
+)
+
+// Response authorization queries, on facts produced by the token rules, which are authority facts, or the verifier
+// policies rules.
+var (
+	// redactQueries return the response fields to clear, i.e., redact("entities.value") or
+	// redact(#authority, "entities.value")
+	redactQueries = []biscuit.Rule{
+		{
+			Head: biscuit.Predicate{Name: "redact", IDs: []biscuit.Atom{biscuit.Variable("0")}},
+			Body: []biscuit.Predicate{{Name: "redact", IDs: []biscuit.Atom{biscuit.Variable("0")}}},
+		},
+		{
+			Head: biscuit.Predicate{Name: "redact", IDs: []biscuit.Atom{biscuit.Variable("0")}},
+			Body: []biscuit.Predicate{{Name: "redact", IDs: []biscuit.Atom{biscuit.Symbol("authority"), biscuit.Variable("0")}}},
+		},
+	}
+	// denyResponseQueries return a fact when the whole response is denied, i.e., deny_response() or
+	// deny_response(#authority)
+	denyResponseQueries = []biscuit.Rule{
+		{
+			Head: biscuit.Predicate{Name: "deny_response"},
+			Body: []biscuit.Predicate{{Name: "deny_response"}},
+		},
+		{
+			Head: biscuit.Predicate{Name: "deny_response"},
+			Body: []biscuit.Predicate{{Name: "deny_response", IDs: []biscuit.Atom{biscuit.Symbol("authority")}}},
+		},
+	}
+)
+
+// authorizeResponse verifies the token again with the ambient facts of the call, plus the resp(#ambient, field, value)
+// facts flattened from resp, and their resp_item, resp_has and resp_oneof forms depending on the configuration, and queries the redact(field) and deny_response() facts. It returns ErrNotAuthorized
+// when the response is denied, or when a redacted field names no field of the response, see protofacts.Redact.
+// It returns the response otherwise, as a copy with the redacted fields cleared if any.
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (v *grpcVerifier) authorizeResponse(fullMethod string, facts []biscuit.Fact, resp interface{}) (interface{}, error) {
+	protoMsg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, ErrInternal.wrap(fmt.Errorf("unsupported response type %T", resp))
+	}
+
+	respFacts, err := v.messageFacts(respFactNames, protoMsg)
+	if err != nil {
+		countLimitError(err)
+		return nil, ErrInternal.wrap(err)
+	}
+
+	verifier, _, err := v.newSignedVerifier()
+	if err != nil {
+		return nil, err
+	}
+	allFacts := make([]biscuit.Fact, 0, len(facts)+len(respFacts))
+	allFacts = append(allFacts, facts...)
+	allFacts = append(allFacts, respFacts...)
+	if err := v.authorize(verifier, fullMethod, subjectResponse, allFacts); err != nil {
+		return nil, err
+	}
+
+	denied, err := queryAll(verifier, denyResponseQueries)
+	if err != nil {
+		return nil, ErrInternal.wrap(err)
+	}
+	if len(denied) > 0 {
+		v.logger.Warn("response denied", zap.String("method", fullMethod))
+		return nil, ErrNotAuthorized.wrap(errors.New("response denied"))
+	}
+
+	redacted, err := queryAll(verifier, redactQueries)
+	if err != nil {
+		return nil, ErrInternal.wrap(err)
+	}
+	if len(redacted) == 0 {
+		return resp, nil
+	}
+
+	// the handler may still hold its response, which must be left untouched
+	out := proto.Clone(protoMsg)
+	redactedFields := make([]string, 0, len(redacted))
+	for _, fact := range redacted {
+		field, ok := fact.IDs[0].(biscuit.String)
+		if !ok {
+			return nil, ErrInternal.wrap(fmt.Errorf("redacted field must be a string, got %v", fact.IDs[0]))
+		}
+		matched, err := protofacts.Redact(out, string(field))
+		if err != nil {
+			return nil, ErrInternal.wrap(err)
+		}
+		if !matched {
+			// a mistyped path would otherwise leak the field it meant to redact
+			v.logger.Warn("response denied, redacted field not found", zap.String("method", fullMethod), zap.String("field", string(field)))
+			return nil, ErrNotAuthorized.wrap(fmt.Errorf("redacted field %q not found in %s", field, out.ProtoReflect().Descriptor().FullName()))
+		}
+		redactedFields = append(redactedFields, string(field))
+	}
+	v.logger.Debug("redacted response fields", zap.String("method", fullMethod), zap.Strings("fields", redactedFields))
+
+	return out, nil
+}
+
+// queryAll returns the facts of every query.
+func queryAll(verifier biscuit.Verifier, queries []biscuit.Rule) (biscuit.FactSet, error) {
+	var facts biscuit.FactSet
+	for _, query := range queries {
+		queried, err := verifier.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		facts = append(facts, queried...)
+	}
+	return facts, nil
+}
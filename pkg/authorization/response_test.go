@@ -0,0 +1,59 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestUnaryResponseAuthorization(t *testing.T) {
+	iss := newTestIssuer(t)
+	core, logs := observer.New(zapcore.DebugLevel)
+	i := iss.interceptor(t, WithResponseAuthorization(), WithLogger(zap.New(core)))
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/authorization.test.Service/Method"}
+	resp := &prototesting.Object{Name: "obj1", Value: 1}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return resp, nil
+	}
+	responseToken := func(head string) []byte {
+		return iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, fmt.Sprintf(`
+			policy "response" {
+				rules {
+					*%s
+						<-  method(#ambient, "Method")
+				}
+
+				caveats {[
+					*allowed_method($0)
+						<-  method(#ambient, $0)
+						@   $0 in ["Method"]
+				]}
+			}
+		`, head)))
+	}
+
+	out, err := i.Unary(iss.signedContext(t, responseToken(`redact("value")`)), &prototesting.Object{}, info, handler)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(&prototesting.Object{Name: "obj1"}, out.(proto.Message)), "got %v", out)
+	require.Equal(t, int64(1), resp.Value, "the handler response is left untouched")
+	require.Equal(t, 1, logs.FilterMessage("authorizing request").Len())
+	require.Equal(t, 1, logs.FilterMessage("authorizing response").Len(), "response facts are logged as such")
+
+	_, err = i.Unary(iss.signedContext(t, responseToken(`redact("unknown")`)), &prototesting.Object{}, info, handler)
+	require.True(t, errors.Is(err, ErrNotAuthorized), "responses are denied when a redacted field is not found")
+
+	_, err = i.Unary(iss.signedContext(t, responseToken("deny_response()")), &prototesting.Object{}, info, handler)
+	require.True(t, errors.Is(err, ErrNotAuthorized))
+}
@@ -2,188 +2,561 @@ package authorization
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"crypto/x509"
 	"demo/pkg/antireplay"
+	"demo/pkg/policy"
+	"demo/pkg/protofacts"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"math"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/flynn/biscuit-go"
 	"github.com/flynn/biscuit-go/cookbook/signedbiscuit"
-	"github.com/flynn/biscuit-go/sig"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-var ErrNotAuthorized = status.Error(codes.PermissionDenied, "not authorized")
-
 const MetadataAuthorization = "authorization"
 
 type BiscuitServerInterceptor interface {
 	Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error)
 	Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error
+	// Stop stops the default anti replay store, once the server stopped. It does nothing when the anti replay
+	// checker was set with WithAntiReplay. Stop can be called several times.
+	Stop()
 }
 
 type biscuitServerInterceptor struct {
 	logger     *zap.Logger
-	pubkey     sig.PublicKey
+	rootKeys   *RootKeyring
+	audiences  *AudienceRegistry
 	antiReplay antireplay.Checker
+	// nonceStore is the store of the default anti replay checker, nil when set with WithAntiReplay.
+	nonceStore *antireplay.RAMStore
+	policies   verifierPolicies
+	exempt     *exemptMethods
+	cache      *authorizationCache
+	now        func() time.Time
+	facts      protofacts.Options
+	providers  []FactProvider
+
+	authorizeResponses bool
 }
 
-func NewBiscuitServerInterceptor(rootPubKey []byte, antiReplay antireplay.Checker, logger *zap.Logger) (BiscuitServerInterceptor, error) {
-	pubkey, err := sig.NewPublicKey(rootPubKey)
+// NewBiscuitServerInterceptor creates an interceptor verifying tokens signed with rootPubKey, or with one of
+// the root keys set using WithRootKeyring, in which case rootPubKey can be nil.
+// At least one audience must be set, using WithAudience or WithServiceAudience. Without WithAntiReplay, the
+// interceptor owns an in memory anti replay store, whose janitor is stopped by Stop.
+func NewBiscuitServerInterceptor(rootPubKey []byte, opts ...ServerInterceptorOption) (BiscuitServerInterceptor, error) {
+	cfg := defaultServerInterceptorConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadPolicyFiles(); err != nil {
+		return nil, err
+	}
+
+	rootKeys, err := cfg.rootKeyring(rootPubKey)
 	if err != nil {
 		return nil, err
 	}
 
+	audiences, err := NewAudienceRegistry(cfg.defaultAudience, cfg.serviceAudiences)
+	if err != nil {
+		return nil, err
+	}
+
+	exempt, err := newExemptMethods(cfg.exemptMethods)
+	if err != nil {
+		return nil, err
+	}
+
+	var cache *authorizationCache
+	if cfg.cache != nil {
+		cache = newAuthorizationCache(*cfg.cache, cfg.now)
+	}
+
+	providers, err := cfg.factProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.logger.Info("loaded verifier policies", zap.Strings("policies", cfg.policies.names()))
+
+	antiReplay := cfg.antiReplay
+	var nonceStore *antireplay.RAMStore
+	if !cfg.antiReplaySet {
+		nonceStore = antireplay.NewRAMStore(antireplay.WithTTL(DefaultNonceMaxAge))
+		antiReplay = antireplay.NewChecker(nonceStore, DefaultNonceWindow, DefaultNonceMaxAge)
+	}
+
 	return &biscuitServerInterceptor{
-		logger:     logger,
+		logger:     cfg.logger,
 		antiReplay: antiReplay,
-		pubkey:     pubkey,
+		nonceStore: nonceStore,
+		rootKeys:   rootKeys,
+		audiences:  audiences,
+		policies:   cfg.policies,
+		exempt:     exempt,
+		cache:      cache,
+		now:        cfg.now,
+		facts:      cfg.facts,
+		providers:  providers,
+
+		authorizeResponses: cfg.authorizeResponses,
 	}, nil
 }
 
 func (i *biscuitServerInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-	verifier, err := i.newVerifierFromCtx(ctx)
+	if i.isExempt(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	verifier, err := i.newVerifierFromCtx(ctx, info.FullMethod)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := verifier.verify(info.FullMethod, req); err != nil {
+	caller, err := verifier.verify(ctx, info.FullMethod, req)
+	if err != nil {
 		return nil, err
 	}
 
-	return handler(ctx, req)
+	resp, err = handler(newCallerContext(ctx, caller), req)
+	if err != nil || !i.authorizeResponses {
+		return resp, err
+	}
+
+	return verifier.authorizeResponse(info.FullMethod, caller.checker.ambientFacts(), resp)
 }
 
+// Stream verifies the token signatures and nonce once when the stream is opened, and then authorizes
+// every message received from the client against the token policy, as unary calls do with their request.
+// Handlers must receive a message before acting on the stream, and sending a message fails until one has been
+// authorized. The stream context holds the verified Caller.
 func (i *biscuitServerInterceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	verifier, err := i.newVerifierFromCtx(ss.Context())
+	if i.isExempt(info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	verifier, err := i.newVerifierFromCtx(ss.Context(), info.FullMethod)
 	if err != nil {
 		return err
 	}
 
-	if err := verifier.verify(info.FullMethod, nil); err != nil {
+	caller, err := verifier.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
 		return err
 	}
-	return handler(srv, ss)
+
+	return handler(srv, &authorizedServerStream{
+		ServerStream:       ss,
+		ctx:                newCallerContext(ss.Context(), caller),
+		verifier:           verifier,
+		fullMethod:         info.FullMethod,
+		authorizeResponses: i.authorizeResponses,
+	})
+}
+
+func (i *biscuitServerInterceptor) Stop() {
+	if i.nonceStore != nil {
+		i.nonceStore.Stop()
+	}
+}
+
+// isExempt returns true and logs the call when fullMethod bypasses the biscuit verification.
+func (i *biscuitServerInterceptor) isExempt(fullMethod string) bool {
+	if !i.exempt.match(fullMethod) {
+		return false
+	}
+
+	i.logger.Info("skipping biscuit verification on exempt method", zap.String("method", fullMethod))
+	return true
+}
+
+// authorizedServerStream wraps a grpc.ServerStream to authorize each received message, and each sent message
+// when response authorization is enabled.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	verifier   *grpcVerifier
+	fullMethod string
+	// authorized is set once a received message has been authorized, and messages can be sent.
+	authorized int32
+
+	authorizeResponses bool
+}
+
+func (s *authorizedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg reads the next message from the stream, and returns an error, such as ErrNotAuthorized when the
+// token policy denies it, which ends the stream when returned from the handler.
+func (s *authorizedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	facts, err := s.verifier.ambientFacts(s.ctx, s.fullMethod, m)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.verifier.authorizeCall(s.fullMethod, facts); err != nil {
+		return err
+	}
+
+	// handler checks now apply to the last received message
+	if caller, ok := CallerFromContext(s.ctx); ok {
+		caller.checker.setFacts(facts)
+	}
+	atomic.StoreInt32(&s.authorized, 1)
+	return nil
+}
+
+// SendMsg returns ErrNotAuthorized until a received message has been authorized. It then authorizes the message
+// with the ambient facts of the last received message, when response authorization is enabled, and sends it with
+// its redacted fields cleared.
+func (s *authorizedServerStream) SendMsg(m interface{}) error {
+	if atomic.LoadInt32(&s.authorized) == 0 {
+		return ErrNotAuthorized.wrap(errors.New("no received message has been authorized on the stream"))
+	}
+	if !s.authorizeResponses {
+		return s.ServerStream.SendMsg(m)
+	}
+
+	caller, ok := CallerFromContext(s.ctx)
+	if !ok {
+		return ErrInternal.wrap(errors.New("no verified caller in stream context"))
+	}
+
+	resp, err := s.verifier.authorizeResponse(s.fullMethod, caller.checker.ambientFacts(), m)
+	if err != nil {
+		return err
+	}
+	return s.ServerStream.SendMsg(resp)
 }
 
 type grpcVerifier struct {
-	verifier   biscuit.Verifier
-	antiReplay antireplay.Checker
-	logger     *zap.Logger
+	biscuit   *biscuit.Biscuit
+	tokenHash string
+	token     *verifiedToken
+	cache     *authorizationCache
+	rootKey   RootKey
+	// rootVerifier is the verifier of the token built when its root key was selected, used by the first signed
+	// verifier instead of verifying the token again. It is nil once used, or when the root key was cached.
+	rootVerifier biscuit.Verifier
+	audience     Audience
+	antiReplay   antireplay.Checker
+	policies     verifierPolicies
+	facts        protofacts.Options
+	providers    []FactProvider
+	sensitive    *sensitiveFields
+	logger       *zap.Logger
 }
 
-func (i *biscuitServerInterceptor) newVerifierFromCtx(ctx context.Context) (*grpcVerifier, error) {
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (i *biscuitServerInterceptor) newVerifierFromCtx(ctx context.Context, fullMethod string) (*grpcVerifier, error) {
+	service, _, err := splitFullMethod(fullMethod)
+	if err != nil {
+		return nil, ErrInternal.wrap(err)
+	}
+	audience, err := i.audiences.Lookup(service)
+	if err != nil {
+		return nil, ErrInternal.wrap(err)
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return nil, errors.New("authorization: failed to retrieve context metadata")
+		return nil, ErrMissingToken.wrap(errors.New("failed to retrieve context metadata"))
 	}
 
 	token, ok := md[MetadataAuthorization]
-	if !ok {
-		return nil, fmt.Errorf("authorization: missing required context metadata %q", MetadataAuthorization)
+	if !ok || len(token) == 0 {
+		return nil, ErrMissingToken.wrap(fmt.Errorf("missing required context metadata %q", MetadataAuthorization))
 	}
 	tokenBytes, err := base64.URLEncoding.DecodeString(token[0])
 	if err != nil {
-		return nil, err
+		return nil, ErrMalformedToken.wrap(err)
 	}
+
 	b, err := biscuit.Unmarshal(tokenBytes)
+	if err != nil {
+		return nil, ErrMalformedToken.wrap(err)
+	}
+
+	// tokens are cached by base token, without the user signature block changing on each call, and verified
+	// for an audience, which is part of their cache key
+	var hash string
+	if i.cache != nil {
+		hash = baseTokenHash(audience.ID, b)
+	}
+
+	var keyIDHint string
+	if keyIDs := md[MetadataRootKeyID]; len(keyIDs) > 0 {
+		keyIDHint = keyIDs[0]
+	}
+
+	cachedToken, cached := i.cache.getToken(hash)
+	var rootKey RootKey
+	var rootVerifier biscuit.Verifier
+	if cached {
+		rootKey = cachedToken.rootKey
+		// the root key may have expired, or been hinted otherwise, since the token was cached
+		if !rootKey.validAt(i.now()) || (keyIDHint != "" && keyIDHint != rootKey.ID) {
+			return nil, ErrInvalidSignature.wrap(fmt.Errorf("root key %q is not valid for the token", rootKey.ID))
+		}
+	} else {
+		rootKey, rootVerifier, err = i.rootKeys.selectKey(b, keyIDHint, i.now())
+		if err != nil {
+			return nil, ErrInvalidSignature.wrap(err)
+		}
+	}
+
+	return &grpcVerifier{
+		biscuit:      b,
+		tokenHash:    hash,
+		token:        cachedToken,
+		cache:        i.cache,
+		rootKey:      rootKey,
+		rootVerifier: rootVerifier,
+		audience:     audience,
+		logger:       i.logger,
+		antiReplay:   i.antiReplay,
+		policies:     i.policies,
+		facts:        i.facts,
+		providers:    i.providers,
+		sensitive:    newSensitiveFields(),
+	}, nil
+}
+
+// verify checks the token signatures, authorizes the call to fullMethod with req, and then checks the
+// signature nonce against replay attempts. It returns the verified caller.
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (v *grpcVerifier) verify(ctx context.Context, fullMethod string, req interface{}) (*Caller, error) {
+	facts, err := v.ambientFacts(ctx, fullMethod, req)
 	if err != nil {
 		return nil, err
 	}
 
-	verifier, err := b.Verify(i.pubkey)
+	signatureMetas, err := v.authorizeCall(fullMethod, facts)
 	if err != nil {
 		return nil, err
 	}
 
-	return &grpcVerifier{
-		verifier:   verifier,
-		logger:     i.logger,
-		antiReplay: i.antiReplay,
-	}, nil
+	if err := v.checkNonce(signatureMetas); err != nil {
+		return nil, err
+	}
+	return newCaller(signatureMetas, newCallChecker(v, fullMethod, facts)), nil
+}
+
+// authenticate checks the token signatures and the signature nonce against replay attempts,
+// without authorizing any method call. It returns the verified caller.
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (v *grpcVerifier) authenticate(ctx context.Context, fullMethod string) (*Caller, error) {
+	facts, err := v.ambientFacts(ctx, fullMethod, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	_, signatureMetas, err := v.newSignedVerifier()
+	if err != nil {
+		return nil, err
+	}
+	v.cacheToken()
+
+	if err := v.checkNonce(signatureMetas); err != nil {
+		return nil, err
+	}
+	return newCaller(signatureMetas, newCallChecker(v, fullMethod, facts)), nil
 }
 
+// authorizeCall verifies the token signatures, and authorizes the call to fullMethod with the ambient facts, unless
+// its decision is cached. It returns the token signature metadata. The signatures are verified even when the decision
+// is cached, as the token signature covers the user signature block, which is new on every call.
 // fullMethod must be the full RPC method string, i.e., /package.service/method.
-func (v *grpcVerifier) verify(fullMethod string, req interface{}) error {
-	var fields map[biscuit.String]biscuit.Atom
+func (v *grpcVerifier) authorizeCall(fullMethod string, facts []biscuit.Fact) (*signedbiscuit.UserSignatureMetadata, error) {
+	// the user signature changes on each call, and is never cached
+	verifier, signatureMetas, err := v.newSignedVerifier()
+	if err != nil {
+		return nil, err
+	}
+	v.cacheToken()
+
+	if decision, ok := v.cache.getDecision(v.tokenHash, fullMethod, facts); ok {
+		v.logger.Debug("authorization cache hit", zap.String("method", fullMethod), zap.Bool("authorized", decision == nil))
+		if decision != nil {
+			return nil, decision
+		}
+		return signatureMetas, nil
+	}
+
+	err = v.authorize(verifier, fullMethod, subjectRequest, facts)
+	if err != nil && !errors.Is(err, ErrNotAuthorized) {
+		return nil, err
+	}
+	v.cache.addDecision(v.tokenHash, fullMethod, facts, v.token, err)
+	if err != nil {
+		return nil, err
+	}
+	return signatureMetas, nil
+}
+
+// cacheToken caches the token once its signatures have been verified.
+func (v *grpcVerifier) cacheToken() {
+	if v.token != nil || v.tokenHash == "" {
+		return
+	}
+
+	v.token = &verifiedToken{rootKey: v.rootKey, expiresAt: tokenExpiry(v.biscuit)}
+	v.cache.addToken(v.tokenHash, v.token)
+}
+
+// newSignedVerifier returns a new verifier for the token, holding the facts proving the validity of its
+// audience and user signatures. Each call returns a fresh verifier, free of any previously added ambient facts.
+func (v *grpcVerifier) newSignedVerifier() (biscuit.Verifier, *signedbiscuit.UserSignatureMetadata, error) {
+	verifier := v.rootVerifier
+	v.rootVerifier = nil
+	if verifier == nil {
+		var err error
+		verifier, err = verifyToken(v.biscuit, v.rootKey.PublicKey)
+		if err != nil {
+			return nil, nil, ErrInvalidSignature.wrap(err)
+		}
+	}
+
+	verifier, signatureMetas, err := signedbiscuit.WithSignatureVerification(verifier, v.audience.ID, v.audience.PublicKey)
+	if err != nil {
+		return nil, nil, ErrInvalidSignature.wrap(err)
+	}
+
+	return verifier, signatureMetas, nil
+}
 
+// ambientFacts returns the service, method and req arguments facts of a call, followed by the facts of the fact
+// providers. req can be nil, when the call doesn't hold any request yet.
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (v *grpcVerifier) ambientFacts(ctx context.Context, fullMethod string, req interface{}) ([]biscuit.Fact, error) {
+	var argFacts []biscuit.Fact
 	if req != nil {
 		protoMsg, ok := req.(proto.Message)
 		if !ok {
-			return errors.New("authorization: invalid request")
+			return nil, ErrInvalidRequest.wrap(fmt.Errorf("unsupported request type %T", req))
 		}
 
-		fields = v.flattenProtoMessage(protoMsg.ProtoReflect())
+		var err error
+		argFacts, err = v.messageFacts(protofacts.DefaultNames, protoMsg)
+		if countLimitError(err) {
+			return nil, ErrRequestTooLarge.wrap(err)
+		}
+		if err != nil {
+			return nil, ErrInvalidRequest.wrap(err)
+		}
 	}
 
-	debugFacts := make([]string, 0, len(fields)+1)
-
-	split := strings.Split(fullMethod, "/")
-	if len(split) != 3 {
-		return errors.New("authorization: failed to split fullMethod")
-	}
-
-	// Add request service, method and arguments to the verifier
-	serviceFact := biscuit.Fact{Predicate: biscuit.Predicate{
-		Name: "service",
-		IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(split[1])},
-	}}
-	v.verifier.AddFact(serviceFact)
-	debugFacts = append(debugFacts, serviceFact.String())
-
-	methodFact := biscuit.Fact{Predicate: biscuit.Predicate{
-		Name: "method",
-		IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(split[2])},
-	}}
-	v.verifier.AddFact(methodFact)
-	debugFacts = append(debugFacts, methodFact.String())
-
-	for name, value := range fields {
-		argFact := biscuit.Fact{Predicate: biscuit.Predicate{
-			Name: "arg",
-			IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), name, value},
-		}}
-		v.verifier.AddFact(argFact)
-		debugFacts = append(debugFacts, argFact.String())
-	}
-	v.logger.Debug("flattened proto request", zap.Strings("facts", debugFacts))
-
-	audiencePubKeyBytes, err := ioutil.ReadFile("./audience.public.demo.key")
+	service, method, err := splitFullMethod(fullMethod)
 	if err != nil {
-		return err
+		return nil, ErrInternal.wrap(err)
 	}
-	audiencePubKey, err := x509.ParsePKIXPublicKey(audiencePubKeyBytes)
-	if err != nil {
-		return err
+
+	facts := make([]biscuit.Fact, 0, len(argFacts)+2)
+	facts = append(facts, ambientFact("service", biscuit.String(service)))
+	facts = append(facts, ambientFact("method", biscuit.String(method)))
+	facts = append(facts, argFacts...)
+
+	for _, provider := range v.providers {
+		providedFacts, err := provider.Facts(ctx, fullMethod, req)
+		if err != nil {
+			var authErr *Error
+			if errors.As(err, &authErr) {
+				return nil, err
+			}
+			return nil, ErrInternal.wrap(fmt.Errorf("fact provider failed: %w", err))
+		}
+		facts = append(facts, providedFacts...)
 	}
 
-	var signatureMetas *signedbiscuit.UserSignatureMetadata
-	v.verifier, signatureMetas, err = signedbiscuit.WithSignatureVerification(v.verifier, "http://audience.local", audiencePubKey.(*ecdsa.PublicKey))
+	return facts, nil
+}
+
+// respFactNames are the predicate names of the facts flattened from the responses.
+var respFactNames = protofacts.Names{Field: "resp", Item: "resp_item", Has: "resp_has", Oneof: "resp_oneof"}
+
+// messageFacts converts msg to facts, as configured, with the given predicate names, i.e., arg(#ambient, field, value)
+// with protofacts.DefaultNames. The names of its sensitive fields are recorded to keep their values out of the logs.
+func (v *grpcVerifier) messageFacts(names protofacts.Names, msg proto.Message) ([]biscuit.Fact, error) {
+	opts := v.facts
+	opts.Names = names
+	result, err := protofacts.Convert(msg, opts)
 	if err != nil {
-		return fmt.Errorf("failed to create signature: %w", err)
+		return nil, err
+	}
+
+	v.sensitive.add(result.Sensitive)
+	return result.Facts, nil
+}
+
+// Subjects of the authorizations, logged with their ambient facts.
+const (
+	subjectRequest  = "request"
+	subjectResponse = "response"
+	subjectCheck    = "handler check"
+)
+
+// authorize adds the ambient facts to the verifier, along with the rules and caveats of the verifier policies
+// selected for fullMethod and the extra policies, and verifies it. subject is the authorized part of the call,
+// logged with the facts.
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (v *grpcVerifier) authorize(verifier biscuit.Verifier, fullMethod, subject string, facts []biscuit.Fact, extraPolicies ...policy.Policy) error {
+	debugFacts := make([]string, 0, len(facts))
+	for _, fact := range facts {
+		verifier.AddFact(fact)
+		debugFacts = append(debugFacts, v.debugFact(fact))
+	}
+	v.logger.Debug("authorizing "+subject, zap.String("method", fullMethod), zap.Strings("facts", debugFacts))
+
+	policies := append(v.policies.forMethod(fullMethod), extraPolicies...)
+	policyNames := make([]string, 0, len(policies))
+	for _, p := range policies {
+		for _, r := range p.Rules {
+			verifier.AddRule(r)
+		}
+		for _, c := range p.Caveats {
+			verifier.AddCaveat(c)
+		}
+		policyNames = append(policyNames, p.Name)
 	}
 
-	if err := v.verifier.Verify(); err != nil {
+	if err := verifier.Verify(); err != nil {
+		// the world holds the sensitive values too
+		world := "<hidden, the call holds sensitive fields>"
+		if v.sensitive.empty() {
+			world = verifier.PrintWorld()
+		}
 		v.logger.Warn("failed to verify biscuit",
 			zap.Error(err),
-			zap.String("world", v.verifier.PrintWorld()),
+			zap.String("subject", subject),
+			zap.String("world", world),
 			zap.Strings("ambient-facts", debugFacts),
+			zap.Strings("verifier-policies", policyNames),
 		)
-		return ErrNotAuthorized
+		return ErrNotAuthorized.wrap(err)
 	}
 
+	return nil
+}
+
+// checkNonce logs the verified signature metadata and runs the anti replay verifications on its nonce.
+func (v *grpcVerifier) checkNonce(signatureMetas *signedbiscuit.UserSignatureMetadata) error {
 	v.logger.Info(
 		"success verifying signed biscuit",
 		zap.String("userID", signatureMetas.UserID),
@@ -193,141 +566,95 @@ func (v *grpcVerifier) verify(fullMethod string, req interface{}) error {
 		zap.Binary("signatureNonce", signatureMetas.UserSignatureNonce),
 	)
 
-	// Anti replay verifications using signatureMetas
-	return v.antiReplay.Check(antireplay.Nonce{
+	err := v.antiReplay.Check(antireplay.Nonce{
 		ID:        signatureMetas.UserEmail,
 		Value:     signatureMetas.UserSignatureNonce,
 		CreatedAt: signatureMetas.UserSignatureTimestamp,
 	})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, antireplay.ErrReplay):
+		return ErrReplay.wrap(err)
+	case errors.Is(err, antireplay.ErrNonceOOB):
+		return ErrNonceOutOfWindow.wrap(err)
+	default:
+		return ErrInternal.wrap(err)
+	}
 }
 
-func (v *grpcVerifier) flattenProtoMessage(msg protoreflect.Message) map[biscuit.String]biscuit.Atom {
-	out := make(flattenedMessage)
-
-	fields := msg.Descriptor().Fields()
-	for i := 0; i < fields.Len(); i++ {
-		field := fields.Get(i)
-
-		var elts map[interface{}]protoreflect.Value
-		var fieldName func(key interface{}) string
-
-		switch {
-		case field.IsMap():
-			m := msg.Get(field).Map()
-			elts = make(map[interface{}]protoreflect.Value, m.Len())
-			m.Range(func(mk protoreflect.MapKey, value protoreflect.Value) bool {
-				elts[mk.Interface()] = value
-				return true
-			})
-			fieldName = func(key interface{}) string {
-				return fmt.Sprintf("%s.%v", field.Name(), key)
-			}
-		default:
-			elts = map[interface{}]protoreflect.Value{struct{}{}: msg.Get(field)}
-			fieldName = func(key interface{}) string {
-				return string(field.Name())
-			}
-		}
-
-		for key, elt := range elts {
-			switch field.Kind() {
-			case protoreflect.BoolKind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					if e.Bool() {
-						out.Insert(biscuit.String(fieldName(key)), biscuit.Integer(1))
-					} else {
-						out.Insert(biscuit.String(fieldName(key)), biscuit.Integer(0))
-					}
-				})
-			case protoreflect.EnumKind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					// swap the enum value to its name from the definition and use it as a string on biscuit side
-					out.Insert(biscuit.String(fieldName(key)), biscuit.String(field.Enum().Values().ByNumber(e.Enum()).Name()))
-				})
-			case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					out.Insert(biscuit.String(fieldName(key)), biscuit.Integer(e.Int()))
-				})
-			case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					out.Insert(biscuit.String(fieldName(key)), biscuit.Integer(e.Uint()))
-				})
-			case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					out.Insert(biscuit.String(fieldName(key)), biscuit.Integer(e.Int()))
-				})
-			case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					if e.Uint() > math.MaxInt64 {
-						v.logger.Warn("uint64 field does not fit in int64", zap.String("field", fieldName(key)))
-						return
-					}
-					out.Insert(biscuit.String(fieldName(key)), biscuit.Integer(e.Uint()))
-				})
-			case protoreflect.StringKind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					out.Insert(biscuit.String(fieldName(key)), biscuit.String(e.String()))
-				})
-			case protoreflect.BytesKind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					out.Insert(biscuit.String(fieldName(key)), biscuit.Bytes(e.Bytes()))
-				})
-			case protoreflect.MessageKind:
-				valuesIterator(field, elt, func(e protoreflect.Value) {
-					switch e.Message().Descriptor().FullName() {
-					case "google.protobuf.Timestamp":
-						ts := e.Message().Interface().(*timestamppb.Timestamp)
-						out.Insert(biscuit.String(fieldName(key)), biscuit.Date(ts.AsTime()))
-					default:
-						// recurse until we only get basic types concatenating sub field name with parent field name
-						subout := v.flattenProtoMessage(e.Message())
-						for k, value := range subout {
-							name := fmt.Sprintf("%s.%s", fieldName(key), string(k))
-							out.Insert(biscuit.String(name), value)
-						}
-					}
-				})
-			default:
-				// Float, Double, Group...
-				v.logger.Warn("unsupported proto kind",
-					zap.String("field", fieldName(key)),
-					zap.String("kind", field.Kind().String()),
-				)
-			}
-		}
+// splitFullMethod returns the service and method names from a full RPC method string, i.e., /package.service/method.
+func splitFullMethod(fullMethod string) (service, method string, err error) {
+	split := strings.Split(fullMethod, "/")
+	if len(split) != 3 {
+		return "", "", errors.New("authorization: failed to split fullMethod")
 	}
+	return split[1], split[2], nil
+}
 
-	return out
+// sensitiveFields holds the names of the sensitive fields flattened during a call, kept out of the logs.
+type sensitiveFields struct {
+	mu    sync.Mutex
+	names map[biscuit.String]struct{}
 }
 
-type flattenedMessage map[biscuit.String]biscuit.Atom
-
-// Insert add the value to the map, at key index. If a value with this key already exists, it will create a
-// biscuit.List and add the original and new values to it. Other inserts at this key will keep appending to the list.
-// When the key doesn't exists, the original value is stored in the map.
-func (f flattenedMessage) Insert(key biscuit.String, value biscuit.Atom) {
-	if v, keyExists := f[key]; keyExists {
-		if l, isSet := v.(biscuit.Set); isSet {
-			f[key] = append(l, value)
-		} else {
-			f[key] = biscuit.Set{v, value}
-		}
+func newSensitiveFields() *sensitiveFields {
+	return &sensitiveFields{names: make(map[biscuit.String]struct{})}
+}
 
+func (s *sensitiveFields) add(names map[biscuit.String]struct{}) {
+	if s == nil || len(names) == 0 {
 		return
 	}
 
-	f[key] = value
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name := range names {
+		s.names[name] = struct{}{}
+	}
 }
 
-// valuesIterator calls cb for every field values (once for regular types, N for repeated types)
-func valuesIterator(field protoreflect.FieldDescriptor, element protoreflect.Value, cb func(e protoreflect.Value)) {
-	if field.IsList() {
-		list := element.List()
-		for i := 0; i < list.Len(); i++ {
-			cb(list.Get(i))
-		}
-		return
+func (s *sensitiveFields) contains(name biscuit.String) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.names[name]
+	return ok
+}
+
+func (s *sensitiveFields) empty() bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.names) == 0
+}
+
+// debugFact returns the fact string for logging, with the value of the sensitive arg and resp facts, and their
+// indexed forms, masked.
+func (v *grpcVerifier) debugFact(fact biscuit.Fact) string {
+	var name biscuit.String
+	switch {
+	case (fact.Name == "arg" || fact.Name == "resp") && len(fact.IDs) == 3:
+		name, _ = fact.IDs[1].(biscuit.String)
+	case (fact.Name == "arg_item" || fact.Name == "resp_item") && len(fact.IDs) == 5:
+		field, _ := fact.IDs[1].(biscuit.String)
+		subField, _ := fact.IDs[3].(biscuit.String)
+		name = field + "." + subField
+	default:
+		return fact.String()
+	}
+	if !v.sensitive.contains(name) {
+		return fact.String()
 	}
 
-	cb(element)
+	ids := append([]biscuit.Atom{}, fact.IDs...)
+	ids[len(ids)-1] = biscuit.String("<sensitive>")
+	masked := biscuit.Fact{Predicate: biscuit.Predicate{Name: fact.Name, IDs: ids}}
+	return masked.String()
 }
@@ -0,0 +1,339 @@
+package authorization
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flynn/biscuit-go/sig"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"demo/pkg/antireplay"
+	"demo/pkg/policy"
+	"demo/pkg/protofacts"
+)
+
+const (
+	// DefaultNonceWindow is the nonce window of the default anti replay checker.
+	DefaultNonceWindow = 5 * time.Second
+	// DefaultNonceMaxAge is the nonce max age of the default anti replay checker.
+	DefaultNonceMaxAge = 60 * time.Minute
+)
+
+// ServerInterceptorOption configures the interceptor created by NewBiscuitServerInterceptor.
+type ServerInterceptorOption func(*serverInterceptorConfig)
+
+type serverInterceptorConfig struct {
+	logger           *zap.Logger
+	antiReplay       antireplay.Checker
+	antiReplaySet    bool
+	defaultAudience  *Audience
+	serviceAudiences map[string]Audience
+	policies         verifierPolicies
+	policyFiles      []string
+	exemptMethods    []string
+	cache            *CacheConfig
+	rootKeys         *RootKeyring
+	now              func() time.Time
+	facts            protofacts.Options
+	providers        []FactProvider
+	timeLocation     *time.Location
+	metadataFacts    *MetadataFactsConfig
+
+	authorizeResponses bool
+}
+
+func defaultServerInterceptorConfig() *serverInterceptorConfig {
+	return &serverInterceptorConfig{
+		logger:           zap.NewNop(),
+		serviceAudiences: make(map[string]Audience),
+		policies:         make(verifierPolicies),
+		now:              time.Now,
+		facts:            protofacts.Options{Limits: DefaultFlattenLimits},
+	}
+}
+
+// loadPolicyFiles merges the policies parsed from the policy files with the ones already configured.
+func (c *serverInterceptorConfig) loadPolicyFiles() error {
+	for _, filename := range c.policyFiles {
+		policies, err := loadVerifierPolicies(filename)
+		if err != nil {
+			return fmt.Errorf("authorization: failed to load verifier policies from %s: %w", filename, err)
+		}
+		for name, p := range policies {
+			if _, exists := c.policies[name]; exists {
+				return fmt.Errorf("authorization: duplicate verifier policy %q in %s", name, filename)
+			}
+			c.policies[name] = p
+		}
+	}
+	return nil
+}
+
+// rootKeyring returns the configured root keyring, holding rootPubKey as well when set.
+func (c *serverInterceptorConfig) rootKeyring(rootPubKey []byte) (*RootKeyring, error) {
+	if len(rootPubKey) == 0 {
+		if c.rootKeys == nil {
+			return nil, errors.New("authorization: a root public key or keyring is required")
+		}
+		return c.rootKeys, nil
+	}
+
+	pubkey, err := sig.NewPublicKey(rootPubKey)
+	if err != nil {
+		return nil, err
+	}
+	if c.rootKeys == nil {
+		return NewRootKeyring(RootKey{PublicKey: pubkey})
+	}
+	keys := append([]RootKey{{PublicKey: pubkey}}, c.rootKeys.keys...)
+	return NewRootKeyring(keys...)
+}
+
+// factProviders returns the built-in fact providers enabled by the options, followed by the configured ones.
+func (c *serverInterceptorConfig) factProviders() ([]FactProvider, error) {
+	var providers []FactProvider
+	if c.timeLocation != nil {
+		providers = append(providers, TimeFactProvider(c.now, c.timeLocation))
+	}
+	if c.metadataFacts != nil {
+		provider, err := MetadataFactProvider(*c.metadataFacts)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return append(providers, c.providers...), nil
+}
+
+func (c *serverInterceptorConfig) validate() error {
+	if c.logger == nil {
+		return errors.New("authorization: logger is required")
+	}
+	if c.antiReplaySet && c.antiReplay == nil {
+		return errors.New("authorization: anti replay checker is required")
+	}
+	if c.now == nil {
+		return errors.New("authorization: clock is required")
+	}
+	for _, provider := range c.providers {
+		if provider == nil {
+			return errors.New("authorization: fact providers can't be nil")
+		}
+	}
+	if err := c.facts.Validate(); err != nil {
+		return fmt.Errorf("authorization: %w", err)
+	}
+	if c.cache != nil {
+		if err := c.cache.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithAudience sets the default audience, expected on calls to services without their own audience.
+func WithAudience(id string, publicKey *ecdsa.PublicKey) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.defaultAudience = &Audience{ID: id, PublicKey: publicKey}
+	}
+}
+
+// WithServiceAudience sets the audience expected on calls to service, given by its full name, i.e., package.service.
+func WithServiceAudience(service, id string, publicKey *ecdsa.PublicKey) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.serviceAudiences[service] = Audience{ID: id, PublicKey: publicKey}
+	}
+}
+
+// WithVerifierPolicies adds server side policies, whose rules and caveats are added to the token verifier on the
+// calls they are selected for, in addition to the ones from the token. Policies are selected by their name:
+// "*" for every call, "package.service" for every method of a service, or "/package.service/method" for a single method.
+func WithVerifierPolicies(policies map[string]policy.Policy) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		for name, p := range policies {
+			c.policies[name] = p
+		}
+	}
+}
+
+// WithVerifierPolicyFile adds the server side policies defined in a policy file, see WithVerifierPolicies.
+// The file is loaded when creating the interceptor.
+func WithVerifierPolicyFile(filename string) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.policyFiles = append(c.policyFiles, filename)
+	}
+}
+
+// WithExemptMethods lists the calls bypassing the biscuit verification, such as health checks or server reflection.
+// Each entry is either a full method name (/package.service/method), a service full name (package.service) matching
+// all of its methods, or a service name prefix ending with "*" (grpc.reflection.*). Exempted calls are still logged.
+func WithExemptMethods(methods ...string) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.exemptMethods = append(c.exemptMethods, methods...)
+	}
+}
+
+// WithAntiReplay replaces the default anti replay checker, an in memory one using DefaultNonceWindow and
+// DefaultNonceMaxAge, with the default antireplay.RAMStore limits, which is only created without this option.
+// The store of antiReplay, if any, is left to the caller to stop.
+func WithAntiReplay(antiReplay antireplay.Checker) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.antiReplay = antiReplay
+		c.antiReplaySet = true
+	}
+}
+
+// WithAuthorizationCache enables the authorization cache. It caches the root keys of the verified tokens, saving
+// the root key selection, and the authorization decisions by method and ambient facts, saving the policy
+// verification when the same token makes the same call again. Tokens are cached by base token, without the user
+// signature block signed on each call. As the token signature covers this block, the token and user signatures
+// are still verified, once, along with the nonce, on every call. Ambient facts changing on each call, such as the
+// time facts, make the decisions hit only when they are equal. Disabled by default.
+func WithAuthorizationCache(cfg CacheConfig) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.cache = &cfg
+	}
+}
+
+// WithRootKeyring sets the root keys accepted by the interceptor, in addition to the rootPubKey given to
+// NewBiscuitServerInterceptor, if any. A token is verified with the root key given by its MetadataRootKeyID
+// metadata hint, or else by its root_key_id(#authority, "keyID") fact, or else with the first currently valid key
+// it is signed with. When the token holds a root_key_id fact, it must match the key ID.
+func WithRootKeyring(keyring *RootKeyring) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.rootKeys = keyring
+	}
+}
+
+// WithResponseAuthorization enables the response authorization. Once the handler succeeds, its response is
+// flattened into resp(#ambient, field, value) facts, and the token is verified again with them and the ambient
+// facts of the call. The response is denied with ErrNotAuthorized when the token or verifier policies rules produce
+// a deny_response() fact, and the fields named by redact(field) facts are cleared, e.g., redact("entities.value").
+// Fields are named as their facts, see protofacts.Redact, and the response is denied when one of them is not found.
+// On streams, every message sent to the client is authorized. Disabled by default.
+func WithResponseAuthorization() ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.authorizeResponses = true
+	}
+}
+
+// WithFloatFormat sets how float and double fields are converted to facts, see protofacts.FloatFormat. The scale is
+// the number of decimal digits kept by the fixed point formats, between 0 and protofacts.MaxFloatScale. Floats are
+// skipped by default.
+func WithFloatFormat(format protofacts.FloatFormat, scale int) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.facts.FloatFormat = format
+		c.facts.FloatScale = scale
+	}
+}
+
+// WithUint64Overflow sets how uint64 and fixed64 values overflowing an int64 are converted to facts,
+// see protofacts.Uint64Overflow. Requests holding such values are rejected by default, with ErrInvalidRequest.
+func WithUint64Overflow(strategy protofacts.Uint64Overflow) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.facts.Uint64Overflow = strategy
+	}
+}
+
+// WithDurationUnit sets the unit of the google.protobuf.Duration facts, i.e., time.Second converts a 1m30s
+// duration to 90. Durations are truncated to the unit. Defaults to protofacts.DefaultDurationUnit.
+func WithDurationUnit(unit time.Duration) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.facts.DurationUnit = unit
+	}
+}
+
+// WithRepeatedFormat sets how the fields of repeated message fields are converted to facts, see
+// protofacts.RepeatedFormat. Defaults to protofacts.RepeatedSet. Response facts use the same format, with resp_item facts.
+func WithRepeatedFormat(format protofacts.RepeatedFormat) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.facts.RepeatedFormat = format
+	}
+}
+
+// WithPresenceFacts adds the has(#ambient, field) facts of the set request fields with explicit presence,
+// i.e., message fields, oneof members and proto3 optional fields, and the oneof(#ambient, name, case) facts of
+// the chosen member of each set oneof. Nested fields are named as their flattened facts. Responses get the
+// resp_has and resp_oneof facts instead. Disabled by default.
+func WithPresenceFacts() ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.facts.PresenceFacts = true
+	}
+}
+
+// WithSkipDefaults leaves out the facts of unset fields, including the proto3 scalars holding their default value,
+// such as an enum set to its first value, which can't be told apart from an unset one. Policies must then
+// handle the missing facts, i.e., when the first enum value is a valid choice. Disabled by default.
+func WithSkipDefaults() ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.facts.SkipDefaults = true
+	}
+}
+
+// WithFlattenLimits sets the limits of the facts flattened from the requests, see FlattenLimits. Requests exceeding
+// them are rejected with ErrRequestTooLarge, and counted in the FlattenLimitsExceededMetric expvar map.
+// Responses are bounded by the same limits, and fail with ErrInternal. Defaults to DefaultFlattenLimits.
+func WithFlattenLimits(limits FlattenLimits) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.facts.Limits = limits
+	}
+}
+
+// WithFactProviders adds providers of ambient facts, added to the verifier of every call after the service,
+// method and arg facts, such as TimeFactProvider or PeerFactProvider. Their facts are part of the authorization
+// cache key, so facts changing on every call, such as the current time, make the decisions cache useless.
+func WithFactProviders(providers ...FactProvider) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.providers = append(c.providers, providers...)
+	}
+}
+
+// WithTimeFacts adds the time(#ambient, date) fact of the current time to every call, given by the interceptor
+// clock (see WithClock), along with the weekday(#ambient, "Monday") and hour(#ambient, 14) facts of this time in loc,
+// UTC when nil, see TimeFactProvider. Policies can then restrict calls to the business hours, or expire short-lived
+// grants with a caveat on the time. The time facts are part of the authorization cache key, so decisions are only
+// reused by calls made in the same second. Disabled by default.
+func WithTimeFacts(loc *time.Location) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		if loc == nil {
+			loc = time.UTC
+		}
+		c.timeLocation = loc
+	}
+}
+
+// WithMetadataFacts adds the header(#ambient, key, value) facts of the incoming metadata keys allowlisted by cfg to
+// every call, i.e., header(#ambient, "x-tenant-id", "tenant1"), see MetadataFactProvider. Calls sending too many
+// or too large values for these keys are rejected with ErrRequestTooLarge. The token keys and the binary keys
+// can't be allowed unless cfg.AllowExcluded is set. Disabled by default.
+func WithMetadataFacts(cfg MetadataFactsConfig) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.metadataFacts = &cfg
+	}
+}
+
+// WithClock sets the function returning the current time. Defaults to time.Now.
+func WithClock(now func() time.Time) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.now = now
+	}
+}
+
+// WithLogger sets the interceptor logger. Nothing is logged by default.
+func WithLogger(logger *zap.Logger) ServerInterceptorOption {
+	return func(c *serverInterceptorConfig) {
+		c.logger = logger
+	}
+}
+
+// ServerOptions returns the grpc.ServerOption installing both the unary and stream interceptors.
+// They are chained, so other interceptors can still be registered on the server.
+func ServerOptions(i BiscuitServerInterceptor) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(i.Unary),
+		grpc.ChainStreamInterceptor(i.Stream),
+	}
+}
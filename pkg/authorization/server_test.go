@@ -1,108 +1,389 @@
 package authorization
 
-//go:generate ../../build/protoc/bin/protoc  --go_out=testing/ --proto_path ../../build/protoc/include --proto_path testing testing/test.proto
-
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"expvar"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/flynn/biscuit-go"
+	"github.com/flynn/biscuit-go/cookbook/signedbiscuit"
+	"github.com/flynn/biscuit-go/sig"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
-	prototesting "demo/pkg/authorization/testing"
+	"demo/pkg/antireplay"
+	"demo/pkg/policy"
+	"demo/pkg/protofacts"
+	prototesting "demo/pkg/protofacts/testing"
 )
 
-func TestFlattenedMessageInsert(t *testing.T) {
-	f := flattenedMessage{}
+const testAudience = "http://audience.local"
+
+// testIssuer issues signed tokens, as cmd/client does on login, and signs them on each call, as the client
+// interceptor does.
+type testIssuer struct {
+	rootKey     sig.Keypair
+	audienceKey *ecdsa.PrivateKey
+	userPrivKey *ecdsa.PrivateKey
+	userKey     *signedbiscuit.UserKeyPair
+	userPubKey  []byte
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	audienceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	userKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	userKeyPair, err := signedbiscuit.NewECDSAKeyPair(userKey)
+	require.NoError(t, err)
+	userPubKey, err := x509.MarshalPKIXPublicKey(&userKey.PublicKey)
+	require.NoError(t, err)
+
+	return &testIssuer{
+		rootKey:     sig.GenerateKeypair(rand.Reader),
+		audienceKey: audienceKey,
+		userPrivKey: userKey,
+		userKey:     userKeyPair,
+		userPubKey:  userPubKey,
+	}
+}
 
-	f.Insert("a", biscuit.String("a"))
-	f.Insert("b", biscuit.String("b"))
-	f.Insert("c", biscuit.Set{biscuit.Integer(0), biscuit.Integer(1)})
+// baseToken returns a token expiring at expireTime, holding the rules and caveats of p, and the extra authority facts.
+func (iss *testIssuer) baseToken(t *testing.T, expireTime time.Time, p policy.Policy, facts ...biscuit.Fact) []byte {
+	builder, err := signedbiscuit.WithSignableFacts(biscuit.NewBuilder(iss.rootKey), testAudience, iss.audienceKey, iss.userPubKey, expireTime, &signedbiscuit.Metadata{
+		UserEmail: "user@email.com",
+		UserID:    "userID",
+		IssueTime: time.Now(),
+	})
+	require.NoError(t, err)
+	for _, f := range facts {
+		require.NoError(t, builder.AddAuthorityFact(f))
+	}
+	for _, r := range p.Rules {
+		require.NoError(t, builder.AddAuthorityRule(r))
+	}
+	for _, c := range p.Caveats {
+		require.NoError(t, builder.AddAuthorityCaveat(c))
+	}
 
-	require.Equal(t, flattenedMessage{
-		"a": biscuit.String("a"),
-		"b": biscuit.String("b"),
-		"c": biscuit.Set{biscuit.Integer(0), biscuit.Integer(1)},
-	}, f)
+	b, err := builder.Build()
+	require.NoError(t, err)
+	token, err := b.Serialize()
+	require.NoError(t, err)
+	return token
+}
 
-	f.Insert("a", biscuit.String("a2"))
-	f.Insert("c", biscuit.Integer(2))
+// sign returns the token signed by the user, with a new nonce.
+func (iss *testIssuer) sign(t *testing.T, token []byte) []byte {
+	signedToken, err := signedbiscuit.Sign(token, iss.rootKey.Public(), iss.userKey)
+	require.NoError(t, err)
+	return signedToken
+}
 
-	require.Equal(t, flattenedMessage{
-		"a": biscuit.Set{biscuit.String("a"), biscuit.String("a2")},
-		"b": biscuit.String("b"),
-		"c": biscuit.Set{biscuit.Integer(0), biscuit.Integer(1), biscuit.Integer(2)},
-	}, f)
+// signedContext returns an incoming context holding the token signed by the user, along with the extra metadata
+// key value pairs.
+func (iss *testIssuer) signedContext(t *testing.T, token []byte, kv ...string) context.Context {
+	md := metadata.Pairs(append([]string{MetadataAuthorization, base64.URLEncoding.EncodeToString(iss.sign(t, token))}, kv...)...)
+	return metadata.NewIncomingContext(context.Background(), md)
 }
 
-func TestGrpcVerifierFlattenProtoMessage(t *testing.T) {
-	now := timestamppb.New(time.Now()).AsTime()
+// interceptor returns an interceptor verifying the tokens of the issuer.
+func (iss *testIssuer) interceptor(t *testing.T, opts ...ServerInterceptorOption) *biscuitServerInterceptor {
+	opts = append([]ServerInterceptorOption{WithAudience(testAudience, &iss.audienceKey.PublicKey)}, opts...)
+	i, err := NewBiscuitServerInterceptor(iss.rootKey.Public().Bytes(), opts...)
+	require.NoError(t, err)
+	return i.(*biscuitServerInterceptor)
+}
 
-	b := make([]byte, 32)
-	_, err := rand.Read(b)
+// parsePolicy returns the single policy defined by src.
+func parsePolicy(t *testing.T, src string) policy.Policy {
+	policies, err := policy.Parse(strings.NewReader(src))
 	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	for _, p := range policies {
+		return p
+	}
+	return policy.Policy{}
+}
+
+// testServerStream is a server stream receiving msgs, in order, and recording the sent messages.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	msgs []proto.Message
+	sent []interface{}
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) RecvMsg(m interface{}) error {
+	if len(s.msgs) == 0 {
+		return errors.New("no more messages")
+	}
+	proto.Merge(m.(proto.Message), s.msgs[0])
+	s.msgs = s.msgs[1:]
+	return nil
+}
+
+func (s *testServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestNewBiscuitServerInterceptor(t *testing.T) {
+	rootPubKey := sig.GenerateKeypair(rand.Reader).Public().Bytes()
+	audienceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = NewBiscuitServerInterceptor(rootPubKey)
+	require.Error(t, err, "an audience is required")
+
+	_, err = NewBiscuitServerInterceptor(rootPubKey, WithAudience("http://audience.local", nil))
+	require.Error(t, err, "the audience public key is required")
+
+	_, err = NewBiscuitServerInterceptor(rootPubKey, WithAudience("http://audience.local", &audienceKey.PublicKey), WithLogger(nil))
+	require.Error(t, err, "the logger can't be unset")
+
+	_, err = NewBiscuitServerInterceptor(rootPubKey, WithAudience("http://audience.local", &audienceKey.PublicKey), WithAntiReplay(nil))
+	require.Error(t, err, "the anti replay checker can't be unset")
+
+	_, err = NewBiscuitServerInterceptor(nil, WithAudience("http://audience.local", &audienceKey.PublicKey))
+	require.Error(t, err, "a root key is required")
+
+	_, err = NewBiscuitServerInterceptor(rootPubKey, WithAudience("http://audience.local", &audienceKey.PublicKey), WithFloatFormat(protofacts.FloatFixedPoint, protofacts.MaxFloatScale+1))
+	require.Error(t, err, "the float scale is bounded")
+
+	_, err = NewBiscuitServerInterceptor(rootPubKey, WithAudience("http://audience.local", &audienceKey.PublicKey), WithFlattenLimits(FlattenLimits{MaxFacts: -1}))
+	require.Error(t, err, "the flatten limits must not be negative")
+
+	_, err = NewBiscuitServerInterceptor(rootPubKey, WithAudience("http://audience.local", &audienceKey.PublicKey), WithFactProviders(nil))
+	require.Error(t, err, "fact providers can't be nil")
 
-	msg := prototesting.Dummy{
-		Enum:          prototesting.Enum_V1,
-		MapBoolObject: map[bool]*prototesting.Object{true: {Name: "bool1", Value: 1}, false: {Name: "bool2", Value: 2}},
-		MapIntObject:  map[int64]*prototesting.Object{41: {Name: "int1", Value: 1}, 42: {Name: "int2", Value: 2}},
-		MapStrObject:  map[string]*prototesting.Object{"a": {Name: "str1", Value: 1}, "b": {Name: "str2", Value: 2}},
-		RepeatedObjects: []*prototesting.Object{
-			{Name: "obj1", Value: 11},
-			{Name: "obj2", Value: 12},
-			{Name: "obj3", Value: 13},
+	keyring, err := NewRootKeyring(RootKey{ID: "k1", PublicKey: sig.GenerateKeypair(rand.Reader).Public()})
+	require.NoError(t, err)
+	_, err = NewBiscuitServerInterceptor(nil, WithAudience("http://audience.local", &audienceKey.PublicKey), WithRootKeyring(keyring))
+	require.NoError(t, err)
+
+	i, err := NewBiscuitServerInterceptor(rootPubKey, WithServiceAudience("demo.api.v1.Demo", "http://audience.local", &audienceKey.PublicKey))
+	require.NoError(t, err)
+	require.Len(t, ServerOptions(i), 2)
+}
+
+func TestBiscuitServerInterceptorStop(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `policy "empty" {}`))
+	info := &grpc.UnaryServerInfo{FullMethod: "/authorization.test.Service/Method"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &prototesting.Object{}, nil
+	}
+
+	i := iss.interceptor(t)
+	require.NotNil(t, i.nonceStore, "the default anti replay store is owned by the interceptor")
+	_, err := i.Unary(iss.signedContext(t, token), &prototesting.Object{}, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, 1, i.nonceStore.Len())
+	i.Stop()
+	i.Stop()
+
+	store := antireplay.NewRAMStore()
+	defer store.Stop()
+	i = iss.interceptor(t, WithAntiReplay(antireplay.NewChecker(store, DefaultNonceWindow, DefaultNonceMaxAge)))
+	require.Nil(t, i.nonceStore, "no default anti replay store is created with WithAntiReplay")
+	_, err = i.Unary(iss.signedContext(t, token), &prototesting.Object{}, info, handler)
+	require.NoError(t, err)
+	i.Stop()
+	require.Equal(t, 1, store.Len())
+	_, err = i.Unary(iss.signedContext(t, token), &prototesting.Object{}, info, handler)
+	require.NoError(t, err, "stores set with WithAntiReplay are left to their owner")
+}
+
+func TestGrpcVerifierFlattenLimits(t *testing.T) {
+	msg := prototesting.Presence{
+		Object: &prototesting.Object{Name: "obj1"},
+		Parent: &prototesting.Presence{
+			Parent: &prototesting.Presence{
+				Object: &prototesting.Object{Name: "obj2"},
+			},
+		},
+		Children: map[string]*prototesting.Presence{
+			"c1": {Target: &prototesting.Presence_Name{Name: "name1"}},
 		},
-		SingleObject: &prototesting.Object{Name: "single1", Value: 12},
-		BooleanTrue:  true,
-		BooleanFalse: false,
-		RepeatedStr:  []string{"a", "b", "c"},
-		Timestamp:    timestamppb.New(now),
-		Bytes:        b,
-		Uint32:       5,
-		Uint64:       6,
-		Sint32:       32,
-		// unsupported types should just get skipped:
-		Float:    3.14,
-		Double:   3.14,
-		Overflow: math.MaxInt64 + 1,
 	}
 
+	testCases := []struct {
+		limits FlattenLimits
+		limit  string
+	}{
+		{limits: FlattenLimits{MaxDepth: 3}},
+		{limits: FlattenLimits{MaxDepth: 2}, limit: protofacts.LimitDepth},
+		{limits: FlattenLimits{MaxFacts: 100}},
+		{limits: FlattenLimits{MaxFacts: 3}, limit: protofacts.LimitFacts},
+		{limits: FlattenLimits{MaxAtomBytes: 32}},
+		{limits: FlattenLimits{MaxAtomBytes: 4}, limit: protofacts.LimitAtomBytes},
+	}
+	for _, testCase := range testCases {
+		v := &grpcVerifier{
+			logger: zap.NewNop(),
+			facts:  protofacts.Options{Limits: testCase.limits},
+		}
+		before := expvarInt(flattenLimitsExceeded.Get(testCase.limit))
+
+		facts, err := v.ambientFacts(context.Background(), "/authorization.test.Service/Method", &msg)
+		if testCase.limit == "" {
+			require.NoError(t, err, "limits %+v", testCase.limits)
+			require.NotEmpty(t, facts)
+			continue
+		}
+		require.True(t, errors.Is(err, ErrRequestTooLarge), "limits %+v", testCase.limits)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		require.Equal(t, before+1, expvarInt(flattenLimitsExceeded.Get(testCase.limit)))
+	}
+}
+
+func TestGrpcVerifierAmbientFacts(t *testing.T) {
 	v := &grpcVerifier{
-		logger: zap.NewNop(),
+		logger:    zap.NewNop(),
+		sensitive: newSensitiveFields(),
+	}
+
+	facts, err := v.ambientFacts(context.Background(), "/authorization.test.Service/Method", &prototesting.WithOptions{
+		Environment: prototesting.Enum_V2,
+		Password:    "secret",
+		Secrets:     []*prototesting.Secret{{Value: "secret2"}},
+	})
+	require.NoError(t, err)
+
+	fact := func(name string, ids ...biscuit.Atom) biscuit.Fact {
+		return biscuit.Fact{Predicate: biscuit.Predicate{Name: name, IDs: append([]biscuit.Atom{biscuit.Symbol("ambient")}, ids...)}}
 	}
+	password := fact("arg", biscuit.String("password"), biscuit.String("secret"))
+	env := fact("arg", biscuit.String("env"), biscuit.String("V2"))
+	require.ElementsMatch(t, []biscuit.Fact{
+		fact("service", biscuit.String("authorization.test.Service")),
+		fact("method", biscuit.String("Method")),
+		env,
+		password,
+		fact("arg", biscuit.String("secrets.value"), biscuit.String("secret2")),
+	}, facts)
+
+	require.NotContains(t, v.debugFact(password), "secret\"", "sensitive values are masked")
+	require.Equal(t, env.String(), v.debugFact(env))
+	item := fact("arg_item", biscuit.String("secrets"), biscuit.Integer(0), biscuit.String("value"), biscuit.String("secret2"))
+	require.NotContains(t, v.debugFact(item), "secret2", "sensitive item values are masked")
+
+	facts, err = v.ambientFacts(context.Background(), "/authorization.test.Service/Method", &prototesting.Dummy{Uint64: math.MaxUint64})
+	require.Nil(t, facts)
+	require.True(t, errors.Is(err, ErrInvalidRequest), "overflowing uint64 values are rejected by default")
+}
+
+func TestStream(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `
+		policy "stream" {
+			caveats {[
+				*allowed_method($0)
+					<-  service(#ambient, "authorization.test.Service"),
+						method(#ambient, $0)
+					@   $0 in ["Method"]
+			], [
+				*allowed_name($0)
+					<-  arg(#ambient, "name", $0)
+					@   $0 in ["obj1"]
+			]}
+		}
+	`))
+	i := iss.interceptor(t)
 
-	out := v.flattenProtoMessage(msg.ProtoReflect())
-	expected := map[biscuit.String]biscuit.Atom{
-		"boolean_true":                biscuit.Integer(1),
-		"boolean_false":               biscuit.Integer(0),
-		"enum":                        biscuit.String("V1"),
-		"map_str_object.b.name":       biscuit.String("str2"),
-		"map_int_object.41.value":     biscuit.Integer(1),
-		"repeated_objects.value":      biscuit.Set{biscuit.Integer(11), biscuit.Integer(12), biscuit.Integer(13)},
-		"map_str_object.b.value":      biscuit.Integer(2),
-		"map_bool_object.false.value": biscuit.Integer(2),
-		"single_object.name":          biscuit.String("single1"),
-		"single_object.value":         biscuit.Integer(12),
-		"timestamp":                   biscuit.Date(now),
-		"map_str_object.a.name":       biscuit.String("str1"),
-		"map_str_object.a.value":      biscuit.Integer(1),
-		"map_int_object.41.name":      biscuit.String("int1"),
-		"map_bool_object.true.value":  biscuit.Integer(1),
-		"repeated_objects.name":       biscuit.Set{biscuit.String("obj1"), biscuit.String("obj2"), biscuit.String("obj3")},
-		"repeated_str":                biscuit.Set{biscuit.String("a"), biscuit.String("b"), biscuit.String("c")},
-		"map_int_object.42.name":      biscuit.String("int2"),
-		"map_int_object.42.value":     biscuit.Integer(2),
-		"map_bool_object.true.name":   biscuit.String("bool1"),
-		"map_bool_object.false.name":  biscuit.String("bool2"),
-		"uint32":                      biscuit.Integer(5),
-		"uint64":                      biscuit.Integer(6),
-		"bytes":                       biscuit.Bytes(b),
-		"sint32":                      biscuit.Integer(32),
+	// handler sends a message before and after receiving one
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		_, ok := CallerFromContext(ss.Context())
+		require.True(t, ok)
+
+		err := ss.SendMsg(&prototesting.Object{Name: "early"})
+		require.True(t, errors.Is(err, ErrNotAuthorized), "messages can't be sent before one is authorized")
+
+		if err := ss.RecvMsg(&prototesting.Object{}); err != nil {
+			return err
+		}
+		return ss.SendMsg(&prototesting.Object{Name: "resp"})
 	}
 
-	require.Equal(t, expected, out)
+	// the stream is opened without any argument, and its first message is authorized
+	ss := &testServerStream{ctx: iss.signedContext(t, token), msgs: []proto.Message{&prototesting.Object{Name: "obj1"}}}
+	require.NoError(t, i.Stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/authorization.test.Service/Method"}, handler))
+	require.Equal(t, []interface{}{&prototesting.Object{Name: "resp"}}, ss.sent)
+
+	ss = &testServerStream{ctx: iss.signedContext(t, token), msgs: []proto.Message{&prototesting.Object{Name: "obj2"}}}
+	err := i.Stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/authorization.test.Service/Method"}, handler)
+	require.True(t, errors.Is(err, ErrNotAuthorized), "denied messages end the stream")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Empty(t, ss.sent)
+
+	ss = &testServerStream{ctx: iss.signedContext(t, token), msgs: []proto.Message{&prototesting.Object{Name: "obj1"}}}
+	err = i.Stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/authorization.test.Service/Other"}, handler)
+	require.True(t, errors.Is(err, ErrNotAuthorized), "methods denied by the token can't receive messages")
+	require.Empty(t, ss.sent)
+
+	ss = &testServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataAuthorization, base64.URLEncoding.EncodeToString(token)))}
+	err = i.Stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/authorization.test.Service/Method"}, func(srv interface{}, ss grpc.ServerStream) error {
+		t.Fatal("streams must not be opened without a valid user signature")
+		return nil
+	})
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+}
+
+func TestAuthorizedServerStreamRecvMsg(t *testing.T) {
+	iss := newTestIssuer(t)
+	token := iss.baseToken(t, time.Now().Add(time.Hour), parsePolicy(t, `
+		policy "stream" {
+			caveats {[
+				*allowed_name($0)
+					<-  arg(#ambient, "name", $0)
+					@   $0 in ["obj1"]
+			]}
+		}
+	`))
+	i := iss.interceptor(t)
+
+	ctx := iss.signedContext(t, token)
+	verifier, err := i.newVerifierFromCtx(ctx, "/authorization.test.Service/Method")
+	require.NoError(t, err)
+
+	ss := &authorizedServerStream{
+		ServerStream: &testServerStream{ctx: ctx, msgs: []proto.Message{
+			&prototesting.Object{Name: "obj1"},
+			&prototesting.Object{Name: "obj2"},
+		}},
+		ctx:        newCallerContext(ctx, &Caller{checker: newCallChecker(verifier, "/authorization.test.Service/Method", nil)}),
+		verifier:   verifier,
+		fullMethod: "/authorization.test.Service/Method",
+	}
+
+	require.NoError(t, ss.RecvMsg(&prototesting.Object{}))
+	err = ss.RecvMsg(&prototesting.Object{})
+	require.True(t, errors.Is(err, ErrNotAuthorized))
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func expvarInt(v expvar.Var) int64 {
+	if i, ok := v.(*expvar.Int); ok {
+		return i.Value()
+	}
+	return 0
 }
@@ -0,0 +1,56 @@
+package authorization
+
+import (
+	"demo/pkg/policy"
+	"os"
+	"sort"
+)
+
+// VerifierPoliciesAll is the name of the verifier policy applying to every call.
+const VerifierPoliciesAll = "*"
+
+// verifierPolicies holds the server side policies, indexed by their name. Their rules and caveats are added
+// to the token verifier, on calls matching their name:
+//   - "*" applies to every call,
+//   - "package.service" applies to every method of the service,
+//   - "/package.service/method" applies to this method only.
+//
+// Policies with other names are never selected on their own.
+type verifierPolicies map[string]policy.Policy
+
+func loadVerifierPolicies(filename string) (verifierPolicies, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return policy.Parse(f)
+}
+
+// forMethod returns the policies applying to a call to fullMethod, from the less to the more specific.
+// fullMethod must be the full RPC method string, i.e., /package.service/method.
+func (p verifierPolicies) forMethod(fullMethod string) []policy.Policy {
+	service, _, err := splitFullMethod(fullMethod)
+	if err != nil {
+		return nil
+	}
+
+	var out []policy.Policy
+	for _, name := range []string{VerifierPoliciesAll, service, fullMethod} {
+		if pol, ok := p[name]; ok {
+			out = append(out, pol)
+		}
+	}
+	return out
+}
+
+// names returns the sorted policy names.
+func (p verifierPolicies) names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
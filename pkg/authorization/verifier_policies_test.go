@@ -0,0 +1,36 @@
+package authorization
+
+import (
+	"demo/pkg/policy"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifierPoliciesForMethod(t *testing.T) {
+	policies := verifierPolicies{
+		"*":                       {Name: "*"},
+		"demo.api.v1.Demo":        {Name: "demo.api.v1.Demo"},
+		"/demo.api.v1.Demo/Read":  {Name: "/demo.api.v1.Demo/Read"},
+		"/demo.api.v1.Other/Read": {Name: "/demo.api.v1.Other/Read"},
+		"named":                   {Name: "named"},
+	}
+
+	require.Equal(t, []policy.Policy{
+		{Name: "*"},
+		{Name: "demo.api.v1.Demo"},
+		{Name: "/demo.api.v1.Demo/Read"},
+	}, policies.forMethod("/demo.api.v1.Demo/Read"))
+
+	require.Equal(t, []policy.Policy{
+		{Name: "*"},
+		{Name: "demo.api.v1.Demo"},
+	}, policies.forMethod("/demo.api.v1.Demo/Create"))
+
+	require.Equal(t, []policy.Policy{
+		{Name: "*"},
+	}, policies.forMethod("/demo.api.v1.Other/Create"))
+
+	require.Empty(t, verifierPolicies{}.forMethod("/demo.api.v1.Demo/Read"))
+	require.Empty(t, policies.forMethod("invalid"))
+}
@@ -0,0 +1,6 @@
+// Package authz provides the proto options controlling the conversion of request fields to facts, i.e.,
+//   string password = 1 [(authz.field).sensitive = true];
+// Proto files import them from "authz/authz.proto", with the pkg directory as proto path.
+package authz
+
+//go:generate ../../build/protoc/bin/protoc  --go_out=paths=source_relative:.. --proto_path ../../build/protoc/include --proto_path .. authz/authz.proto
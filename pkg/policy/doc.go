@@ -0,0 +1,44 @@
+// Package policy parses policy files, holding named sets of biscuit rules and caveats:
+//
+//	// comment
+//	policy "name" {
+//	    rules {
+//	        *head($0) <- body(#ambient, $0) @ $0 in ["a", "b"]
+//	    }
+//	    caveats {[
+//	        *first_choice() <- ...
+//	    ||
+//	        *second_choice() <- ...
+//	    ], [
+//	        *other_caveat() <- ...
+//	    ]}
+//	}
+//
+// Each caveat is a list of queries separated by ||, succeeding when any of them succeeds.
+//
+// # Time
+//
+// When the server interceptor is created with authorization.WithTimeFacts, every call holds the
+// time(#ambient, date) fact of the current time, and the weekday(#ambient, day) and hour(#ambient, hour) facts of
+// this time in the configured timezone, the day being its English name, i.e., "Monday", and the hour an integer
+// between 0 and 23. A call without these facts fails the caveats below, so time restricted policies deny every
+// call on a server not adding them.
+//
+// Calls can be restricted to the business hours, with the hours compared as integers:
+//
+//	*business_hours()
+//	    <-  weekday(#ambient, $0),
+//	        hour(#ambient, $1)
+//	    @   $0 in ["Monday", "Tuesday", "Wednesday", "Thursday", "Friday"],
+//	        $1 >= 9,
+//	        $1 < 18
+//
+// A short-lived grant, such as a break-glass access during an incident, expires with a caveat comparing the time to
+// an RFC 3339 date, set by the token issuer:
+//
+//	*not_expired($0)
+//	    <-  time(#ambient, $0)
+//	    @   $0 <= "2021-03-04T12:00:00Z"
+//
+// The date comparisons are inclusive: <= for before, and >= for after a date.
+package policy
@@ -0,0 +1,97 @@
+package protofacts
+
+import (
+	"sync"
+
+	"github.com/flynn/biscuit-go"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// compiledMessages caches the compiledMessage of every flattened message descriptor.
+// Descriptors are only compiled once, so the authz options are not decoded again on every call.
+var compiledMessages sync.Map // map[protoreflect.MessageDescriptor]*compiledMessage
+
+// compiledMessage is the flattening plan of a message descriptor: its authz options, and the fields to flatten.
+type compiledMessage struct {
+	skip      bool
+	sensitive bool
+	// fields are the message fields, in declaration order, without the skipped ones
+	fields []compiledField
+}
+
+// compiledField is a message field to flatten.
+type compiledField struct {
+	desc protoreflect.FieldDescriptor
+	// value describes the values of the field: the map values for maps, or else the field itself
+	value protoreflect.FieldDescriptor
+	// name is the field name, or its alias
+	name      string
+	sensitive bool
+	// enumNames are the value names of enum fields, by number
+	enumNames map[protoreflect.EnumNumber]biscuit.String
+}
+
+// compiledMessageOf returns the compiledMessage of desc, compiling it on first use.
+func compiledMessageOf(desc protoreflect.MessageDescriptor) *compiledMessage {
+	if compiled, ok := compiledMessages.Load(desc); ok {
+		return compiled.(*compiledMessage)
+	}
+	// concurrent calls may compile the same descriptor, only the first one stored is kept
+	compiled, _ := compiledMessages.LoadOrStore(desc, compileMessage(desc))
+	return compiled.(*compiledMessage)
+}
+
+// compileMessage builds the flattening plan of desc. Message fields are compiled on their own, when first flattened,
+// so recursive messages compile as well.
+func compileMessage(desc protoreflect.MessageDescriptor) *compiledMessage {
+	msgOpts := messageOptions(desc)
+	compiled := &compiledMessage{
+		skip:      msgOpts.GetSkip(),
+		sensitive: msgOpts.GetSensitive(),
+	}
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fieldOpts := fieldOptions(field)
+		if fieldOpts.GetSkip() {
+			continue
+		}
+
+		compiledField := compiledField{
+			desc:      field,
+			value:     field,
+			name:      string(field.Name()),
+			sensitive: fieldOpts.GetSensitive(),
+		}
+		if alias := fieldOpts.GetAlias(); alias != "" {
+			compiledField.name = alias
+		}
+		if field.IsMap() {
+			compiledField.value = field.MapValue()
+		}
+		if compiledField.value.Kind() == protoreflect.EnumKind {
+			values := compiledField.value.Enum().Values()
+			compiledField.enumNames = make(map[protoreflect.EnumNumber]biscuit.String, values.Len())
+			for j := 0; j < values.Len(); j++ {
+				compiledField.enumNames[values.Get(j).Number()] = biscuit.String(values.Get(j).Name())
+			}
+		}
+		compiled.fields = append(compiled.fields, compiledField)
+	}
+
+	return compiled
+}
+
+// enumName returns the name of an enum value, or its number when unknown, as open enums can hold any number.
+func (f *compiledField) enumName(n protoreflect.EnumNumber) biscuit.Atom {
+	if name, ok := f.enumNames[n]; ok {
+		return name
+	}
+	return biscuit.Integer(n)
+}
+
+// hasMessageValues returns true when the field values are messages, other than well known types.
+func (f *compiledField) hasMessageValues() bool {
+	return f.value.Kind() == protoreflect.MessageKind && !isWellKnownType(f.value.Message())
+}
@@ -0,0 +1,64 @@
+package protofacts
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/stretchr/testify/require"
+
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestCompileMessage(t *testing.T) {
+	compiled := compileMessage((&prototesting.WithOptions{}).ProtoReflect().Descriptor())
+	require.False(t, compiled.skip)
+	require.False(t, compiled.sensitive)
+
+	var names []string
+	var sensitive []string
+	for _, field := range compiled.fields {
+		names = append(names, field.name)
+		if field.sensitive {
+			sensitive = append(sensitive, field.name)
+		}
+	}
+	require.Equal(t, []string{"env", "password", "secret", "skipped", "obj", "secrets"}, names)
+	require.Equal(t, []string{"password"}, sensitive)
+	require.Equal(t, biscuit.String("V2"), compiled.fields[0].enumName(prototesting.Enum_V2.Number()))
+	require.Equal(t, biscuit.Integer(42), compiled.fields[0].enumName(42), "unknown enum values are kept as numbers")
+
+	require.True(t, compileMessage((&prototesting.Secret{}).ProtoReflect().Descriptor()).sensitive)
+	require.True(t, compileMessage((&prototesting.Skipped{}).ProtoReflect().Descriptor()).skip)
+}
+
+func TestCompiledMessageOf(t *testing.T) {
+	desc := (&prototesting.Presence{}).ProtoReflect().Descriptor()
+
+	var wg sync.WaitGroup
+	results := make([]*compiledMessage, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = compiledMessageOf(desc)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, compiled := range results {
+		require.Same(t, results[0], compiled)
+	}
+}
+
+func TestConverterFlattenScalarMap(t *testing.T) {
+	msg := prototesting.Dummy{
+		MapStrEnum: map[string]prototesting.Enum{"a": prototesting.Enum_V3, "b": 42},
+	}
+	c := newConverter(Options{})
+
+	out, err := c.flattenFields(msg.ProtoReflect())
+	require.NoError(t, err)
+	require.Equal(t, biscuit.String("V3"), out["map_str_enum.a"])
+	require.Equal(t, biscuit.Integer(42), out["map_str_enum.b"])
+}
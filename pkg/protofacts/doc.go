@@ -0,0 +1,63 @@
+// Package protofacts converts protobuf messages to biscuit facts, as the authorization server interceptor does
+// for the call requests, so other tools can produce the same facts for a message.
+//
+// # Facts
+//
+// Every fact holds the #ambient symbol as first term. With DefaultNames, a message produces:
+//
+//	arg(#ambient, path, value)                       a field value
+//	arg_item(#ambient, path, index, subPath, value)  a field of an element of a repeated message field
+//	has(#ambient, path)                              a set field with explicit presence
+//	oneof(#ambient, path, fieldName)                 the set member of a oneof
+//
+// The item facts depend on Options.RepeatedFormat, and the has and oneof facts on Options.PresenceFacts.
+// Facts are returned in no particular order.
+//
+// # Paths
+//
+// A path names a value from the converted message:
+//   - a field of the message is named after the field name, i.e., "env", or its (authz.field).alias
+//   - a field of a message field is named after the message field path, a dot, and its own name, i.e., "entity.name"
+//   - a map entry value is named after the map field path, a dot, and its key: strings as is, integers in decimal,
+//     and booleans as true or false, i.e., "labels.team", "stuff2.42.name" or "stuff3.true.name". Keys are not
+//     escaped, so keys holding dots are ambiguous.
+//
+// The fields and messages with the (authz.field).skip or (authz.message).skip options have no facts, and the paths
+// of the ones with the sensitive options are listed in Result.Sensitive. Redact clears the fields of a message
+// named by a path, as response redactions do.
+//
+// # Values
+//
+// Field values are converted to:
+//   - bool: the integer 1 or 0
+//   - enum: the string of the value name, or the integer of its number when it has no name
+//   - int32, int64, uint32, sint32, sint64, fixed32, sfixed32, sfixed64: an integer
+//   - uint64, fixed64: an integer, or according to Options.Uint64Overflow above math.MaxInt64
+//   - float, double: according to Options.FloatFormat, left out by default
+//   - string: a string
+//   - bytes: bytes
+//   - message: the facts of its fields, on their own paths
+//   - google.protobuf.Timestamp: a date
+//   - google.protobuf.Duration: an integer, in Options.DurationUnit
+//   - wrappers, i.e., google.protobuf.Int64Value: their wrapped value, on the field path
+//   - google.protobuf.Struct, Value and ListValue: as JSON, objects adding their keys to the path, numbers being
+//     integers when integral, or else following Options.FloatFormat, booleans 1 or 0, lists sets, and null left out
+//   - google.protobuf.FieldMask: the set of its paths
+//   - google.protobuf.Any: the facts of its message, resolved from the global registry, along with its type URL
+//     on path.@type
+//
+// # Repeated values
+//
+// A path holding a single value has a single fact with it. A path holding several values, from a repeated field,
+// or a field of the elements of a repeated message field, has a single fact with the set of its values, i.e.,
+// arg(#ambient, "entities.name", ["entity1", "entity2"]). The values of an element can't be told apart from the
+// other elements in sets, so RepeatedIndexed adds, or uses instead, the item facts, holding the repeated field
+// path, the element index, and the path of a field in the element, i.e., arg_item(#ambient, "entities", 0, "name", "entity1").
+// An empty repeated field has no fact.
+//
+// # Unset fields
+//
+// Unset message fields have no facts. Unset scalar fields have the facts of their default value, i.e., the empty
+// string or the first enum value, unless Options.SkipDefaults is set. The has facts tell the set message fields,
+// oneof members, and proto2 or proto3 optional fields apart from the unset ones.
+package protofacts
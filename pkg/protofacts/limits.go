@@ -0,0 +1,96 @@
+package protofacts
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flynn/biscuit-go"
+)
+
+// Limits bounds the facts converted from a message, so a large message can't make a verifier do an unbounded
+// amount of work. A zero limit is disabled.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of message fields, the fields of the message itself being at depth 0.
+	// The objects and lists nested in a google.protobuf.Struct or Value, and the message of an Any, are a level each.
+	MaxDepth int
+	// MaxFacts is the maximum number of facts converted from the message, a set counting as many facts as it holds values.
+	// Values are counted while the message is converted, fields and indexed items together, which stops once they exceed
+	// the limit.
+	MaxFacts int
+	// MaxAtomBytes is the maximum size of a string or bytes value, or of a field path, which holds the map keys.
+	MaxAtomBytes int
+}
+
+// Names of the limits, set on LimitError.
+const (
+	LimitDepth     = "depth"
+	LimitFacts     = "facts"
+	LimitAtomBytes = "atom_bytes"
+)
+
+// LimitError is returned when a message exceeds one of the Limits.
+type LimitError struct {
+	// Limit is the name of the exceeded limit, i.e., LimitDepth.
+	Limit string
+	// Max is the value of the exceeded limit.
+	Max int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("protofacts: %s limit of %d exceeded", e.Limit, e.Max)
+}
+
+func (l Limits) validate() error {
+	if l.MaxDepth < 0 || l.MaxFacts < 0 || l.MaxAtomBytes < 0 {
+		return errors.New("protofacts: limits must not be negative")
+	}
+	return nil
+}
+
+// checkDepth returns a LimitError when depth exceeds MaxDepth.
+func (l Limits) checkDepth(depth int) error {
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return &LimitError{Limit: LimitDepth, Max: l.MaxDepth}
+	}
+	return nil
+}
+
+// checkFacts returns a LimitError when facts exceed MaxFacts or MaxAtomBytes.
+func (l Limits) checkFacts(facts []biscuit.Fact) error {
+	count := 0
+	for _, fact := range facts {
+		count++
+		for _, id := range fact.IDs {
+			if set, ok := id.(biscuit.Set); ok && len(set) > 1 {
+				count += len(set) - 1
+			}
+			if !l.atomFits(id) {
+				return &LimitError{Limit: LimitAtomBytes, Max: l.MaxAtomBytes}
+			}
+		}
+		if l.MaxFacts > 0 && count > l.MaxFacts {
+			return &LimitError{Limit: LimitFacts, Max: l.MaxFacts}
+		}
+	}
+	return nil
+}
+
+// atomFits returns whether the strings and bytes of atom, or of its values when it is a set, fit in MaxAtomBytes.
+func (l Limits) atomFits(atom biscuit.Atom) bool {
+	if l.MaxAtomBytes <= 0 {
+		return true
+	}
+	switch a := atom.(type) {
+	case biscuit.String:
+		return len(a) <= l.MaxAtomBytes
+	case biscuit.Bytes:
+		return len(a) <= l.MaxAtomBytes
+	case biscuit.Set:
+		for _, value := range a {
+			if !l.atomFits(value) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package protofacts
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultDurationUnit is the unit of the google.protobuf.Duration facts, unless set in the Options.
+const DefaultDurationUnit = time.Millisecond
+
+// FloatFormat selects how float and double fields are converted to facts.
+type FloatFormat int
+
+const (
+	// FloatSkip leaves float and double fields out of the facts.
+	FloatSkip FloatFormat = iota
+	// FloatFixedPoint converts values to integers, multiplied by 10^scale and rounded up from their shortest
+	// decimal form, i.e., 12.341 is arg(#ambient, "price", 1235) with a scale of 2, and -12.349 is -1234. Rounding
+	// up keeps upper bounds exact: $0 <= 1000 holds only for values up to 10.00, while a lower bound such as
+	// $0 >= 1000 holds for values above 9.99. Values overflowing an int64, NaN or infinities are left out.
+	FloatFixedPoint
+	// FloatString converts values to their shortest decimal string representation, i.e., arg(#ambient, "price", "12.345").
+	FloatString
+	// FloatBoth converts values as FloatFixedPoint, and adds their FloatString form on the field name
+	// suffixed with FloatStringSuffix, i.e., arg(#ambient, "price.str", "12.345").
+	FloatBoth
+)
+
+// FloatStringSuffix is appended to the field name of the string form of floats, with FloatBoth.
+const FloatStringSuffix = ".str"
+
+// MaxFloatScale is the maximum scale of the fixed point float formats.
+const MaxFloatScale = 18
+
+// Uint64Overflow selects how uint64 and fixed64 values above math.MaxInt64, which don't fit in a biscuit integer,
+// are converted to facts.
+type Uint64Overflow int
+
+const (
+	// Uint64Reject fails the whole conversion. It is the default, as leaving the value out could let a policy
+	// pass on the missing fact.
+	Uint64Reject Uint64Overflow = iota
+	// Uint64String converts values to their decimal string, i.e., arg(#ambient, "id", "18446744073709551615").
+	Uint64String
+	// Uint64Bytes converts values to their 8 bytes big endian form, i.e., arg(#ambient, "id", hex:ffffffffffffffff).
+	Uint64Bytes
+	// Uint64Clamp converts values to math.MaxInt64.
+	Uint64Clamp
+)
+
+// RepeatedFormat selects how the fields of repeated message fields are converted to facts.
+type RepeatedFormat int
+
+const (
+	// RepeatedSet converts the sub fields of every element to a single set, i.e.,
+	// arg(#ambient, "entities.name", ["entity1", "entity2"]), losing which name goes with which value.
+	RepeatedSet RepeatedFormat = iota
+	// RepeatedIndexed converts them to indexed facts instead, holding the field name, element index, sub field name
+	// and value, i.e., arg_item(#ambient, "entities", 0, "name", "entity1"), so policies can correlate the
+	// fields of a same element.
+	RepeatedIndexed
+	// RepeatedSetAndIndexed converts them to both forms.
+	RepeatedSetAndIndexed
+)
+
+// Names are the predicate names of the facts converted from a message.
+type Names struct {
+	// Field is the name of the field facts, i.e., arg(#ambient, "entity.name", "entity1").
+	Field string
+	// Item is the name of the indexed facts of repeated message fields, i.e., arg_item(#ambient, "entities", 0, "name", "entity1").
+	Item string
+	// Has is the name of the presence facts, i.e., has(#ambient, "entity").
+	Has string
+	// Oneof is the name of the oneof facts, i.e., oneof(#ambient, "target", "name").
+	Oneof string
+}
+
+// DefaultNames are the names of the facts converted from requests, used when Options.Names is unset.
+var DefaultNames = Names{Field: "arg", Item: "arg_item", Has: "has", Oneof: "oneof"}
+
+// Options configures the conversion of messages to facts. The zero value converts repeated message fields to
+// sets, leaves the floats out, rejects overflowing uint64 values, and has no limits.
+type Options struct {
+	// Names are the predicate names of the facts. Defaults to DefaultNames.
+	Names Names
+	// FloatFormat sets how float and double fields are converted, see FloatFormat.
+	FloatFormat FloatFormat
+	// FloatScale is the number of decimal digits kept by the fixed point float formats, between 0 and MaxFloatScale.
+	FloatScale int
+	// Uint64Overflow sets how uint64 and fixed64 values overflowing an int64 are converted, see Uint64Overflow.
+	Uint64Overflow Uint64Overflow
+	// DurationUnit is the unit of the google.protobuf.Duration facts, i.e., time.Second converts a 1m30s
+	// duration to 90. Durations are truncated to the unit. Defaults to DefaultDurationUnit.
+	DurationUnit time.Duration
+	// RepeatedFormat sets how the fields of repeated message fields are converted, see RepeatedFormat.
+	RepeatedFormat RepeatedFormat
+	// PresenceFacts adds the Has facts of the set fields with explicit presence, i.e., message fields, oneof
+	// members and proto3 optional fields, and the Oneof facts of the chosen member of each set oneof.
+	PresenceFacts bool
+	// SkipDefaults leaves out the facts of unset fields, including the proto3 scalars holding their default value,
+	// such as an enum set to its first value, which can't be told apart from an unset one.
+	SkipDefaults bool
+	// Limits bounds the converted facts. Messages exceeding them fail with a *LimitError.
+	Limits Limits
+}
+
+// Validate returns an error when the options are out of range.
+func (o Options) Validate() error {
+	if o.FloatScale < 0 || o.FloatScale > MaxFloatScale {
+		return fmt.Errorf("protofacts: float scale must be between 0 and %d", MaxFloatScale)
+	}
+	if o.DurationUnit < 0 {
+		return errors.New("protofacts: duration unit must not be negative")
+	}
+	return o.Limits.validate()
+}
+
+// withDefaults returns the options, with the defaults of the unset names and duration unit.
+func (o Options) withDefaults() Options {
+	if o.Names == (Names{}) {
+		o.Names = DefaultNames
+	}
+	if o.DurationUnit == 0 {
+		o.DurationUnit = DefaultDurationUnit
+	}
+	return o
+}
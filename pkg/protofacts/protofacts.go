@@ -0,0 +1,638 @@
+package protofacts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"demo/pkg/authz"
+
+	"github.com/flynn/biscuit-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Result is the conversion of a message to facts.
+type Result struct {
+	Facts []biscuit.Fact
+	// Sensitive are the paths of the fields marked sensitive with the authz proto options. The values of their
+	// Field and Item facts should be kept out of the logs.
+	Sensitive map[biscuit.String]struct{}
+}
+
+// Convert converts msg to facts, as specified in the package documentation. It returns a *LimitError when msg
+// exceeds the limits of opts, or an error when a value can't be converted, such as an overflowing uint64 value
+// with Uint64Reject, or an Any of an unknown type.
+func Convert(msg proto.Message, opts Options) (*Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := newConverter(opts)
+	facts, err := c.messageFacts(msg.ProtoReflect())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Facts: facts, Sensitive: c.sensitive}, nil
+}
+
+// Facts converts msg to facts, see Convert.
+func Facts(msg proto.Message, opts Options) ([]biscuit.Fact, error) {
+	result, err := Convert(msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Facts, nil
+}
+
+// converter converts a message to facts, and collects the paths of its sensitive fields.
+type converter struct {
+	opts      Options
+	sensitive map[biscuit.String]struct{}
+	// inserted is the number of values inserted by the current flattening, checked against the MaxFacts limit
+	// while flattening, so large messages are rejected early
+	inserted int
+}
+
+func newConverter(opts Options) *converter {
+	return &converter{
+		opts:      opts.withDefaults(),
+		sensitive: make(map[biscuit.String]struct{}),
+	}
+}
+
+// messageFacts converts msg to facts, i.e., arg(#ambient, field, value). Depending on the configured RepeatedFormat,
+// the fields of repeated messages are also, or instead, added as indexed facts, i.e.,
+// arg_item(#ambient, "entities", 0, "name", "entity1"). With presence facts enabled, has(#ambient, field) and
+// oneof(#ambient, name, case) facts are added for the set fields with explicit presence.
+func (c *converter) messageFacts(msg protoreflect.Message) ([]biscuit.Fact, error) {
+	names := c.opts.Names
+	fields, err := c.flattenFields(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []flattenedItem
+	if c.opts.RepeatedFormat != RepeatedSet {
+		// the items replace the fields of the repeated messages with RepeatedIndexed, whose values are removed,
+		// and uncounted, as their items are flattened, so the count holds the values of both passes otherwise
+		var replaced flattenedMessage
+		if c.opts.RepeatedFormat == RepeatedIndexed {
+			replaced = fields
+		}
+		items, err = c.flattenItems(msg, "", replaced)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	facts := make([]biscuit.Fact, 0, len(fields)+len(items))
+	for name, value := range fields {
+		facts = append(facts, biscuit.Fact{Predicate: biscuit.Predicate{
+			Name: names.Field,
+			IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), name, value},
+		}})
+	}
+	for _, item := range items {
+		facts = append(facts, biscuit.Fact{Predicate: biscuit.Predicate{
+			Name: names.Item,
+			IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), item.field, biscuit.Integer(item.index), item.subField, item.value},
+		}})
+	}
+	if c.opts.PresenceFacts {
+		facts = append(facts, c.presenceFacts(msg, "")...)
+	}
+	if err := c.opts.Limits.checkFacts(facts); err != nil {
+		return nil, err
+	}
+
+	return facts, nil
+}
+
+// flattenFields converts the msg fields to a map of values by field path. It returns an error when a field
+// value can't be converted, such as overflowing uint64 values with Uint64Reject.
+// The paths of the sensitive fields, set with the authz proto options, are recorded to keep their values out of the logs.
+func (c *converter) flattenFields(msg protoreflect.Message) (map[biscuit.String]biscuit.Atom, error) {
+	out := make(flattenedMessage)
+	if err := c.flattenMessage(out, c.sensitive, msg, "", 0); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// flattenMessage converts the msg fields as flattenFields does into out, prefixing their names with prefix,
+// and adds the names of its sensitive fields to sensitive, unless nil. It walks the compiledMessage of msg, so
+// fields and messages are skipped, renamed or marked sensitive according to their authz options.
+// depth is the nesting depth of msg, checked against the MaxDepth limit.
+func (c *converter) flattenMessage(out flattenedMessage, sensitive map[biscuit.String]struct{}, msg protoreflect.Message, prefix string, depth int) error {
+	if err := c.opts.Limits.checkDepth(depth); err != nil {
+		return err
+	}
+
+	compiled := compiledMessageOf(msg.Descriptor())
+	if compiled.skip {
+		return nil
+	}
+
+	for i := range compiled.fields {
+		field := &compiled.fields[i]
+		if c.opts.SkipDefaults && !msg.Has(field.desc) {
+			// unset, or holding the default value without explicit presence
+			continue
+		}
+		name := prefix + field.name
+
+		var err error
+		switch {
+		case field.desc.IsMap():
+			msg.Get(field.desc).Map().Range(func(mk protoreflect.MapKey, value protoreflect.Value) bool {
+				err = c.insertValue(out, sensitive, field, name+"."+mk.String(), value, depth)
+				return err == nil
+			})
+		case field.desc.IsList():
+			list := msg.Get(field.desc).List()
+			for j := 0; j < list.Len() && err == nil; j++ {
+				err = c.insertValue(out, sensitive, field, name, list.Get(j), depth)
+			}
+		default:
+			err = c.insertValue(out, sensitive, field, name, msg.Get(field.desc), depth)
+		}
+		if err != nil {
+			return err
+		}
+
+		if sensitive != nil && (compiled.sensitive || field.sensitive) {
+			for k := range out {
+				if string(k) == name || strings.HasPrefix(string(k), name+".") {
+					sensitive[k] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// insertValue adds a single value of field to out, at name. Repeated fields and maps have their values added one by one.
+func (c *converter) insertValue(out flattenedMessage, sensitive map[biscuit.String]struct{}, field *compiledField, name string, value protoreflect.Value, depth int) error {
+	switch field.value.Kind() {
+	case protoreflect.BoolKind:
+		if value.Bool() {
+			c.insert(out, biscuit.String(name), biscuit.Integer(1))
+		} else {
+			c.insert(out, biscuit.String(name), biscuit.Integer(0))
+		}
+	case protoreflect.EnumKind:
+		// swap the enum value to its name from the definition and use it as a string on biscuit side
+		c.insert(out, biscuit.String(name), field.enumName(value.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		c.insert(out, biscuit.String(name), biscuit.Integer(value.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		c.insert(out, biscuit.String(name), biscuit.Integer(value.Uint()))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if value.Uint() <= math.MaxInt64 {
+			c.insert(out, biscuit.String(name), biscuit.Integer(value.Uint()))
+		} else if err := c.insertUint64Overflow(out, name, value.Uint()); err != nil {
+			return err
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		c.insertFloat(out, name, field.value.Kind(), value.Float())
+	case protoreflect.StringKind:
+		c.insert(out, biscuit.String(name), biscuit.String(value.String()))
+	case protoreflect.BytesKind:
+		c.insert(out, biscuit.String(name), biscuit.Bytes(value.Bytes()))
+	case protoreflect.MessageKind:
+		return c.insertMessage(out, sensitive, name, value.Message(), depth+1)
+	default:
+		// groups are unsupported, and left out
+	}
+	return c.checkInserted()
+}
+
+// insertMessage adds the msg fields to out, named after the parent field name. Unset messages add nothing.
+// Well known types are converted to their semantic value instead:
+//   - Timestamp is a date
+//   - Duration is an integer, in the configured duration unit
+//   - wrappers are their wrapped value
+//   - Struct, Value and ListValue are flattened as JSON, see insertStructValue
+//   - FieldMask is the set of its paths
+//   - Any is its resolved message, from the global registry, along with its type URL in name.@type
+//
+// depth is the nesting depth of msg. The objects and lists nested in a Struct, and the message of an Any, are
+// one level deeper than their parent, so they are bounded by the MaxDepth limit as message fields are.
+func (c *converter) insertMessage(out flattenedMessage, sensitive map[biscuit.String]struct{}, name string, msg protoreflect.Message, depth int) error {
+	if !msg.IsValid() {
+		// unset messages hold no value, and recursing in them would never end on recursive message types
+		return nil
+	}
+	if err := c.opts.Limits.checkDepth(depth); err != nil {
+		return err
+	}
+
+	fullName := msg.Descriptor().FullName()
+	switch fullName {
+	case "google.protobuf.Timestamp":
+		ts := msg.Interface().(*timestamppb.Timestamp)
+		c.insert(out, biscuit.String(name), biscuit.Date(ts.AsTime()))
+	case "google.protobuf.Duration":
+		d := msg.Interface().(*durationpb.Duration).AsDuration()
+		c.insert(out, biscuit.String(name), biscuit.Integer(d/c.opts.DurationUnit))
+	case "google.protobuf.Struct":
+		for k, value := range msg.Interface().(*structpb.Struct).Fields {
+			if err := c.insertStructValue(out, name+"."+k, value, depth); err != nil {
+				return err
+			}
+		}
+	case "google.protobuf.Value":
+		return c.insertStructValue(out, name, msg.Interface().(*structpb.Value), depth)
+	case "google.protobuf.ListValue":
+		for _, value := range msg.Interface().(*structpb.ListValue).Values {
+			if err := c.insertStructValue(out, name, value, depth); err != nil {
+				return err
+			}
+		}
+	case "google.protobuf.FieldMask":
+		paths := msg.Interface().(*fieldmaskpb.FieldMask).Paths
+		set := make(biscuit.Set, 0, len(paths))
+		for _, path := range paths {
+			set = append(set, biscuit.String(path))
+		}
+		c.insert(out, biscuit.String(name), set)
+	case "google.protobuf.Any":
+		a := msg.Interface().(*anypb.Any)
+		msgType, err := protoregistry.GlobalTypes.FindMessageByURL(a.TypeUrl)
+		if err != nil {
+			return fmt.Errorf("field %s: failed to resolve any type %q: %w", name, a.TypeUrl, err)
+		}
+		resolved := msgType.New().Interface()
+		if err := proto.Unmarshal(a.Value, resolved); err != nil {
+			return fmt.Errorf("field %s: failed to unmarshal any type %q: %w", name, a.TypeUrl, err)
+		}
+		c.insert(out, biscuit.String(name+".@type"), biscuit.String(a.TypeUrl))
+		return c.insertMessage(out, sensitive, name, resolved.ProtoReflect(), depth+1)
+	default:
+		if _, isWrapper := wrapperTypes[fullName]; isWrapper {
+			// the wrapped value, along with its string form on FloatBoth
+			field := &compiledMessageOf(msg.Descriptor()).fields[0]
+			return c.insertValue(out, sensitive, field, name, msg.Get(field.desc), depth)
+		}
+		// recurse until we only get basic types concatenating sub field name with parent field name
+		return c.flattenMessage(out, sensitive, msg, name+".", depth)
+	}
+
+	return c.checkInserted()
+}
+
+// wrapperTypes are the well known wrapper types, holding a single value field.
+var wrapperTypes = map[protoreflect.FullName]struct{}{
+	"google.protobuf.DoubleValue": {},
+	"google.protobuf.FloatValue":  {},
+	"google.protobuf.Int64Value":  {},
+	"google.protobuf.UInt64Value": {},
+	"google.protobuf.Int32Value":  {},
+	"google.protobuf.UInt32Value": {},
+	"google.protobuf.BoolValue":   {},
+	"google.protobuf.StringValue": {},
+	"google.protobuf.BytesValue":  {},
+}
+
+// insertStructValue adds a google.protobuf.Value to out, as JSON: objects are flattened with their keys concatenated
+// to the name, lists are sets, booleans are 0 or 1 as bool fields, and null values are left out. Integral numbers
+// fitting an int64 are integers, other numbers follow the configured float format. depth is the nesting depth of
+// value, the values of the objects and lists it holds being one level deeper.
+func (c *converter) insertStructValue(out flattenedMessage, name string, value *structpb.Value, depth int) error {
+	switch kind := value.GetKind().(type) {
+	case *structpb.Value_NumberValue:
+		n := kind.NumberValue
+		if n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 {
+			c.insert(out, biscuit.String(name), biscuit.Integer(int64(n)))
+		} else {
+			c.insertFloat(out, name, protoreflect.DoubleKind, n)
+		}
+	case *structpb.Value_StringValue:
+		c.insert(out, biscuit.String(name), biscuit.String(kind.StringValue))
+	case *structpb.Value_BoolValue:
+		if kind.BoolValue {
+			c.insert(out, biscuit.String(name), biscuit.Integer(1))
+		} else {
+			c.insert(out, biscuit.String(name), biscuit.Integer(0))
+		}
+	case *structpb.Value_StructValue:
+		if err := c.opts.Limits.checkDepth(depth + 1); err != nil {
+			return err
+		}
+		for k, fieldValue := range kind.StructValue.GetFields() {
+			if err := c.insertStructValue(out, name+"."+k, fieldValue, depth+1); err != nil {
+				return err
+			}
+		}
+	case *structpb.Value_ListValue:
+		if err := c.opts.Limits.checkDepth(depth + 1); err != nil {
+			return err
+		}
+		for _, listValue := range kind.ListValue.GetValues() {
+			if err := c.insertStructValue(out, name, listValue, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return c.checkInserted()
+}
+
+// flattenedItem is a flattened field of an element of a repeated message field.
+type flattenedItem struct {
+	// field is the name of the repeated field, as flattenFields names it
+	field    biscuit.String
+	index    int
+	subField biscuit.String
+	value    biscuit.Atom
+}
+
+// flattenItems returns the flattened fields of every element of the repeated message fields of msg, prefixing
+// their names with prefix. Elements are flattened as flattenFields does, so repeated message fields nested
+// in an element are sets in its items. Repeated well known types have no items.
+// When replaced isn't nil, the flattened fields of each repeated message field are removed from it, and from the
+// inserted values count, before its items are flattened.
+func (c *converter) flattenItems(msg protoreflect.Message, prefix string, replaced flattenedMessage) ([]flattenedItem, error) {
+	compiled := compiledMessageOf(msg.Descriptor())
+	if compiled.skip {
+		return nil, nil
+	}
+
+	var items []flattenedItem
+	for i := range compiled.fields {
+		field := &compiled.fields[i]
+		if !field.hasMessageValues() || !msg.Has(field.desc) {
+			continue
+		}
+		name := prefix + field.name
+
+		switch {
+		case field.desc.IsMap():
+			var err error
+			msg.Get(field.desc).Map().Range(func(mk protoreflect.MapKey, value protoreflect.Value) bool {
+				var subItems []flattenedItem
+				subItems, err = c.flattenItems(value.Message(), name+"."+mk.String()+".", replaced)
+				items = append(items, subItems...)
+				return err == nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		case field.desc.IsList():
+			c.remove(replaced, name+".")
+			list := msg.Get(field.desc).List()
+			for j := 0; j < list.Len(); j++ {
+				elt := make(flattenedMessage)
+				// the depth of the whole message has already been checked by flattenFields
+				if err := c.flattenMessage(elt, nil, list.Get(j).Message(), "", 0); err != nil {
+					return nil, err
+				}
+				for subField, value := range elt {
+					items = append(items, flattenedItem{
+						field:    biscuit.String(name),
+						index:    j,
+						subField: subField,
+						value:    value,
+					})
+				}
+			}
+		default:
+			subItems, err := c.flattenItems(msg.Get(field.desc).Message(), name+".", replaced)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, subItems...)
+		}
+	}
+
+	return items, nil
+}
+
+// presenceFacts returns the has(#ambient, field) facts of the set fields of msg with explicit presence, i.e.,
+// message fields and oneof members, and the oneof(#ambient, name, case) facts of its set oneofs, prefixing their
+// names with prefix. Elements of repeated fields have no presence facts.
+func (c *converter) presenceFacts(msg protoreflect.Message, prefix string) []biscuit.Fact {
+	names := c.opts.Names
+	compiled := compiledMessageOf(msg.Descriptor())
+	if compiled.skip {
+		return nil
+	}
+
+	var facts []biscuit.Fact
+	for i := range compiled.fields {
+		field := &compiled.fields[i]
+		if !msg.Has(field.desc) {
+			continue
+		}
+		name := prefix + field.name
+
+		if hasPresence(field.desc) {
+			facts = append(facts, biscuit.Fact{Predicate: biscuit.Predicate{
+				Name: names.Has,
+				IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(name)},
+			}})
+		}
+		if oneof := field.desc.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			facts = append(facts, biscuit.Fact{Predicate: biscuit.Predicate{
+				Name: names.Oneof,
+				IDs:  []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(prefix + string(oneof.Name())), biscuit.String(field.name)},
+			}})
+		}
+
+		switch {
+		case !field.hasMessageValues(), field.desc.IsList():
+			continue
+		case field.desc.IsMap():
+			msg.Get(field.desc).Map().Range(func(mk protoreflect.MapKey, value protoreflect.Value) bool {
+				facts = append(facts, c.presenceFacts(value.Message(), name+"."+mk.String()+".")...)
+				return true
+			})
+		default:
+			facts = append(facts, c.presenceFacts(msg.Get(field.desc).Message(), name+".")...)
+		}
+	}
+
+	return facts
+}
+
+// hasPresence returns true when the field tracks whether it is set, rather than holding a default value when unset.
+func hasPresence(field protoreflect.FieldDescriptor) bool {
+	if field.Cardinality() == protoreflect.Repeated {
+		return false
+	}
+	return field.Syntax() == protoreflect.Proto2 || field.Kind() == protoreflect.MessageKind || field.ContainingOneof() != nil
+}
+
+func isWellKnownType(msg protoreflect.MessageDescriptor) bool {
+	return msg.FullName().Parent() == "google.protobuf"
+}
+
+// fieldOptions returns the authz options of the field, or nil when unset.
+func fieldOptions(field protoreflect.FieldDescriptor) *authz.FieldOptions {
+	opts, ok := field.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return nil
+	}
+	fieldOpts, _ := proto.GetExtension(opts, authz.E_Field).(*authz.FieldOptions)
+	return fieldOpts
+}
+
+// messageOptions returns the authz options of the message, or nil when unset.
+func messageOptions(msg protoreflect.MessageDescriptor) *authz.MessageOptions {
+	opts, ok := msg.Options().(*descriptorpb.MessageOptions)
+	if !ok || opts == nil {
+		return nil
+	}
+	msgOpts, _ := proto.GetExtension(opts, authz.E_Message).(*authz.MessageOptions)
+	return msgOpts
+}
+
+// insertUint64Overflow adds the uint64 value u, overflowing an int64, to out, with the configured Uint64Overflow
+// strategy. It returns an error with Uint64Reject.
+func (c *converter) insertUint64Overflow(out flattenedMessage, name string, u uint64) error {
+	switch c.opts.Uint64Overflow {
+	case Uint64String:
+		c.insert(out, biscuit.String(name), biscuit.String(strconv.FormatUint(u, 10)))
+	case Uint64Bytes:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, u)
+		c.insert(out, biscuit.String(name), biscuit.Bytes(b))
+	case Uint64Clamp:
+		c.insert(out, biscuit.String(name), biscuit.Integer(math.MaxInt64))
+	default:
+		return fmt.Errorf("uint64 field %s value %d overflows int64", name, u)
+	}
+	return nil
+}
+
+// insertFloat adds the float or double value f to out, in the configured FloatFormat.
+func (c *converter) insertFloat(out flattenedMessage, name string, kind protoreflect.Kind, f float64) {
+	if c.opts.FloatFormat == FloatFixedPoint || c.opts.FloatFormat == FloatBoth {
+		// values not fitting an int64 are left out
+		if i, ok := fixedPoint(f, floatBitSize(kind), c.opts.FloatScale); ok {
+			c.insert(out, biscuit.String(name), biscuit.Integer(i))
+		}
+	}
+
+	str := biscuit.String(strconv.FormatFloat(f, 'g', -1, floatBitSize(kind)))
+	switch c.opts.FloatFormat {
+	case FloatString:
+		c.insert(out, biscuit.String(name), str)
+	case FloatBoth:
+		c.insert(out, biscuit.String(name+FloatStringSuffix), str)
+	}
+}
+
+// floatBitSize returns the size of the float kind values.
+func floatBitSize(kind protoreflect.Kind) int {
+	if kind == protoreflect.FloatKind {
+		return 32
+	}
+	return 64
+}
+
+// fixedPoint returns f multiplied by 10^scale, rounded up, or false when it isn't finite or overflows an int64.
+// The shortest decimal form of f for its bit size is scaled, rather than its binary value, so that 1.005 is
+// 101 and not 100.49999999999999 rounded, and 1.1 is 110 and not 110.00000000000001 rounded up.
+func fixedPoint(f float64, bitSize, scale int) (int64, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+
+	digits := strconv.FormatFloat(f, 'f', -1, bitSize)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+	intPart, fracPart := digits, ""
+	if dot := strings.IndexByte(digits, '.'); dot >= 0 {
+		intPart, fracPart = digits[:dot], digits[dot+1:]
+	}
+
+	// positive values are rounded up when digits are cut, negative ones are truncated toward zero
+	var roundUp bool
+	if len(fracPart) > scale {
+		roundUp = !negative && strings.Trim(fracPart[scale:], "0") != ""
+		fracPart = fracPart[:scale]
+	} else {
+		fracPart += strings.Repeat("0", scale-len(fracPart))
+	}
+
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	i, err := strconv.ParseInt(sign+intPart+fracPart, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if roundUp {
+		if i == math.MaxInt64 {
+			return 0, false
+		}
+		i++
+	}
+	return i, true
+}
+
+type flattenedMessage map[biscuit.String]biscuit.Atom
+
+// Insert add the value to the map, at key index. If a value with this key already exists, it will create a
+// biscuit.List and add the original and new values to it. Other inserts at this key will keep appending to the list.
+// When the key doesn't exists, the original value is stored in the map.
+func (f flattenedMessage) Insert(key biscuit.String, value biscuit.Atom) {
+	if v, keyExists := f[key]; keyExists {
+		if l, isSet := v.(biscuit.Set); isSet {
+			f[key] = append(l, value)
+		} else {
+			f[key] = biscuit.Set{v, value}
+		}
+
+		return
+	}
+
+	f[key] = value
+}
+
+// insert adds value to out at key, as flattenedMessage.Insert does, and counts it, along with the values of sets.
+func (c *converter) insert(out flattenedMessage, key biscuit.String, value biscuit.Atom) {
+	out.Insert(key, value)
+	if set, ok := value.(biscuit.Set); ok {
+		c.inserted += len(set)
+	} else {
+		c.inserted++
+	}
+}
+
+// remove deletes the values of out whose key starts with prefix, and uncounts them.
+func (c *converter) remove(out flattenedMessage, prefix string) {
+	for key, value := range out {
+		if !strings.HasPrefix(string(key), prefix) {
+			continue
+		}
+		delete(out, key)
+		if set, ok := value.(biscuit.Set); ok {
+			c.inserted -= len(set)
+		} else {
+			c.inserted--
+		}
+	}
+}
+
+// checkInserted returns a LimitError when the inserted values exceed MaxFacts, each of them being a fact or a set
+// value, so messages exceeding the limit are rejected without being flattened whole.
+func (c *converter) checkInserted() error {
+	if c.opts.Limits.MaxFacts > 0 && c.inserted > c.opts.Limits.MaxFacts {
+		return &LimitError{Limit: LimitFacts, Max: c.opts.Limits.MaxFacts}
+	}
+	return nil
+}
@@ -0,0 +1,622 @@
+package protofacts
+
+//go:generate ../../build/protoc/bin/protoc  --go_out=testing/ --proto_path ../../build/protoc/include --proto_path .. --proto_path testing testing/test.proto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/flynn/biscuit-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"demo/pkg/pb"
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestFlattenedMessageInsert(t *testing.T) {
+	f := flattenedMessage{}
+
+	f.Insert("a", biscuit.String("a"))
+	f.Insert("b", biscuit.String("b"))
+	f.Insert("c", biscuit.Set{biscuit.Integer(0), biscuit.Integer(1)})
+
+	require.Equal(t, flattenedMessage{
+		"a": biscuit.String("a"),
+		"b": biscuit.String("b"),
+		"c": biscuit.Set{biscuit.Integer(0), biscuit.Integer(1)},
+	}, f)
+
+	f.Insert("a", biscuit.String("a2"))
+	f.Insert("c", biscuit.Integer(2))
+
+	require.Equal(t, flattenedMessage{
+		"a": biscuit.Set{biscuit.String("a"), biscuit.String("a2")},
+		"b": biscuit.String("b"),
+		"c": biscuit.Set{biscuit.Integer(0), biscuit.Integer(1), biscuit.Integer(2)},
+	}, f)
+}
+
+func TestConverterFlattenFields(t *testing.T) {
+	now := timestamppb.New(time.Now()).AsTime()
+
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+
+	msg := prototesting.Dummy{
+		Enum:          prototesting.Enum_V1,
+		MapBoolObject: map[bool]*prototesting.Object{true: {Name: "bool1", Value: 1}, false: {Name: "bool2", Value: 2}},
+		MapIntObject:  map[int64]*prototesting.Object{41: {Name: "int1", Value: 1}, 42: {Name: "int2", Value: 2}},
+		MapStrObject:  map[string]*prototesting.Object{"a": {Name: "str1", Value: 1}, "b": {Name: "str2", Value: 2}},
+		RepeatedObjects: []*prototesting.Object{
+			{Name: "obj1", Value: 11},
+			{Name: "obj2", Value: 12},
+			{Name: "obj3", Value: 13},
+		},
+		SingleObject: &prototesting.Object{Name: "single1", Value: 12},
+		BooleanTrue:  true,
+		BooleanFalse: false,
+		RepeatedStr:  []string{"a", "b", "c"},
+		Timestamp:    timestamppb.New(now),
+		Bytes:        b,
+		Uint32:       5,
+		Uint64:       6,
+		Sint32:       32,
+		// floats are skipped by default
+		Float:  3.14,
+		Double: 3.14,
+		// overflowing uint64 are rejected by default
+		Overflow: math.MaxInt64 + 1,
+	}
+
+	c := newConverter(Options{})
+
+	_, err = c.flattenFields(msg.ProtoReflect())
+	require.Error(t, err, "overflowing uint64 values are rejected by default")
+
+	c = newConverter(Options{Uint64Overflow: Uint64String})
+	out, err := c.flattenFields(msg.ProtoReflect())
+	require.NoError(t, err)
+	expected := map[biscuit.String]biscuit.Atom{
+		"boolean_true":                biscuit.Integer(1),
+		"boolean_false":               biscuit.Integer(0),
+		"enum":                        biscuit.String("V1"),
+		"map_str_object.b.name":       biscuit.String("str2"),
+		"map_int_object.41.value":     biscuit.Integer(1),
+		"repeated_objects.value":      biscuit.Set{biscuit.Integer(11), biscuit.Integer(12), biscuit.Integer(13)},
+		"map_str_object.b.value":      biscuit.Integer(2),
+		"map_bool_object.false.value": biscuit.Integer(2),
+		"single_object.name":          biscuit.String("single1"),
+		"single_object.value":         biscuit.Integer(12),
+		"timestamp":                   biscuit.Date(now),
+		"map_str_object.a.name":       biscuit.String("str1"),
+		"map_str_object.a.value":      biscuit.Integer(1),
+		"map_int_object.41.name":      biscuit.String("int1"),
+		"map_bool_object.true.value":  biscuit.Integer(1),
+		"repeated_objects.name":       biscuit.Set{biscuit.String("obj1"), biscuit.String("obj2"), biscuit.String("obj3")},
+		"repeated_str":                biscuit.Set{biscuit.String("a"), biscuit.String("b"), biscuit.String("c")},
+		"map_int_object.42.name":      biscuit.String("int2"),
+		"map_int_object.42.value":     biscuit.Integer(2),
+		"map_bool_object.true.name":   biscuit.String("bool1"),
+		"map_bool_object.false.name":  biscuit.String("bool2"),
+		"uint32":                      biscuit.Integer(5),
+		"uint64":                      biscuit.Integer(6),
+		"bytes":                       biscuit.Bytes(b),
+		"sint32":                      biscuit.Integer(32),
+		"overflow":                    biscuit.String("9223372036854775808"),
+	}
+
+	require.Equal(t, expected, out)
+
+	floatTestCases := []struct {
+		format   FloatFormat
+		scale    int
+		expected map[biscuit.String]biscuit.Atom
+	}{
+		{
+			format:   FloatFixedPoint,
+			scale:    2,
+			expected: map[biscuit.String]biscuit.Atom{"float": biscuit.Integer(314), "double": biscuit.Integer(314)},
+		},
+		{
+			format:   FloatString,
+			expected: map[biscuit.String]biscuit.Atom{"float": biscuit.String("3.14"), "double": biscuit.String("3.14")},
+		},
+		{
+			format: FloatBoth,
+			scale:  3,
+			expected: map[biscuit.String]biscuit.Atom{
+				"float":      biscuit.Integer(3140),
+				"float.str":  biscuit.String("3.14"),
+				"double":     biscuit.Integer(3140),
+				"double.str": biscuit.String("3.14"),
+			},
+		},
+	}
+	for _, testCase := range floatTestCases {
+		c = newConverter(Options{FloatFormat: testCase.format, FloatScale: testCase.scale, Uint64Overflow: Uint64String})
+		out, err := c.flattenFields(msg.ProtoReflect())
+		require.NoError(t, err)
+		require.Len(t, out, len(expected)+len(testCase.expected))
+		for name, value := range testCase.expected {
+			require.Equal(t, value, out[name], "format %d, field %s", testCase.format, name)
+		}
+	}
+}
+
+func TestConverterFlattenUint64Overflow(t *testing.T) {
+	msg := prototesting.Dummy{
+		Uint64: math.MaxUint64,
+	}
+	c := newConverter(Options{})
+
+	testCases := []struct {
+		strategy Uint64Overflow
+		expected biscuit.Atom
+	}{
+		{strategy: Uint64String, expected: biscuit.String("18446744073709551615")},
+		{strategy: Uint64Bytes, expected: biscuit.Bytes{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{strategy: Uint64Clamp, expected: biscuit.Integer(math.MaxInt64)},
+	}
+	for _, testCase := range testCases {
+		c = newConverter(Options{Uint64Overflow: testCase.strategy})
+		out, err := c.flattenFields(msg.ProtoReflect())
+		require.NoError(t, err)
+		require.Equal(t, testCase.expected, out["uint64"], "strategy %d", testCase.strategy)
+	}
+
+	c = newConverter(Options{Uint64Overflow: Uint64Reject})
+	_, err := c.flattenFields(msg.ProtoReflect())
+	require.Error(t, err)
+
+	_, err = Facts(&msg, Options{})
+	require.Error(t, err)
+}
+
+func TestConverterFlattenProtoOptions(t *testing.T) {
+	msg := prototesting.WithOptions{
+		Environment: prototesting.Enum_V2,
+		Blob:        []byte("blob"),
+		Password:    "secret",
+		Secret:      &prototesting.Secret{Value: "secret1"},
+		Skipped:     &prototesting.Skipped{Value: "skipped"},
+		Object:      &prototesting.Object{Name: "obj1", Value: 1},
+		Secrets:     []*prototesting.Secret{{Value: "secret2"}, {Value: "secret3"}},
+	}
+
+	c := newConverter(Options{})
+
+	out, err := c.flattenFields(msg.ProtoReflect())
+	require.NoError(t, err)
+	require.Equal(t, map[biscuit.String]biscuit.Atom{
+		"env":           biscuit.String("V2"),
+		"password":      biscuit.String("secret"),
+		"secret.value":  biscuit.String("secret1"),
+		"obj.name":      biscuit.String("obj1"),
+		"obj.value":     biscuit.Integer(1),
+		"secrets.value": biscuit.Set{biscuit.String("secret2"), biscuit.String("secret3")},
+	}, out)
+
+	require.Equal(t, map[biscuit.String]struct{}{
+		"password":      {},
+		"secret.value":  {},
+		"secrets.value": {},
+	}, c.sensitive)
+
+}
+
+func TestConverterFlattenWellKnownTypes(t *testing.T) {
+	object, err := anypb.New(&prototesting.Object{Name: "obj1", Value: 1})
+	require.NoError(t, err)
+	jsonStruct, err := structpb.NewStruct(map[string]interface{}{
+		"name":  "struct1",
+		"count": 3,
+		"ratio": 0.5,
+		"admin": true,
+		"none":  nil,
+		"tags":  []interface{}{"a", "b"},
+		"owner": map[string]interface{}{"id": "user1"},
+	})
+	require.NoError(t, err)
+
+	msg := prototesting.WellKnown{
+		Duration:    durationpb.New(90 * time.Second),
+		DoubleValue: wrapperspb.Double(3.14),
+		Int64Value:  wrapperspb.Int64(-1),
+		Uint64Value: wrapperspb.UInt64(2),
+		BoolValue:   wrapperspb.Bool(false),
+		StringValue: wrapperspb.String("str"),
+		BytesValue:  wrapperspb.Bytes([]byte("bytes")),
+		Struct:      jsonStruct,
+		Value:       structpb.NewStringValue("value1"),
+		List:        &structpb.ListValue{Values: []*structpb.Value{structpb.NewNumberValue(1), structpb.NewNumberValue(2)}},
+		FieldMask:   &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+		Any:         object,
+		Durations:   []*durationpb.Duration{durationpb.New(time.Second), durationpb.New(time.Minute)},
+	}
+
+	c := newConverter(Options{FloatFormat: FloatString})
+
+	out, err := c.flattenFields(msg.ProtoReflect())
+	require.NoError(t, err)
+	require.Equal(t, map[biscuit.String]biscuit.Atom{
+		"duration":        biscuit.Integer(90000),
+		"double_value":    biscuit.String("3.14"),
+		"int64_value":     biscuit.Integer(-1),
+		"uint64_value":    biscuit.Integer(2),
+		"bool_value":      biscuit.Integer(0),
+		"string_value":    biscuit.String("str"),
+		"bytes_value":     biscuit.Bytes("bytes"),
+		"struct.name":     biscuit.String("struct1"),
+		"struct.count":    biscuit.Integer(3),
+		"struct.ratio":    biscuit.String("0.5"),
+		"struct.admin":    biscuit.Integer(1),
+		"struct.tags":     biscuit.Set{biscuit.String("a"), biscuit.String("b")},
+		"struct.owner.id": biscuit.String("user1"),
+		"value":           biscuit.String("value1"),
+		"list":            biscuit.Set{biscuit.Integer(1), biscuit.Integer(2)},
+		"field_mask":      biscuit.Set{biscuit.String("name")},
+		"any.@type":       biscuit.String("type.googleapis.com/protofacts.test.Object"),
+		"any.name":        biscuit.String("obj1"),
+		"any.value":       biscuit.Integer(1),
+		"durations":       biscuit.Set{biscuit.Integer(1000), biscuit.Integer(60000)},
+	}, out)
+
+	c.opts.DurationUnit = time.Second
+	out, err = c.flattenFields(msg.ProtoReflect())
+	require.NoError(t, err)
+	require.Equal(t, biscuit.Integer(90), out["duration"])
+
+	// unresolvable any types are rejected
+	msg.Any = &anypb.Any{TypeUrl: "type.googleapis.com/unknown.Type"}
+	_, err = c.flattenFields(msg.ProtoReflect())
+	require.Error(t, err)
+}
+
+func TestConverterMessageFacts(t *testing.T) {
+	msg := prototesting.WithOptions{
+		Object:  &prototesting.Object{Name: "obj1", Value: 1},
+		Secrets: []*prototesting.Secret{{Value: "secret1"}, {Value: "secret2"}},
+	}
+
+	arg := func(name string, value biscuit.Atom) biscuit.Fact {
+		return biscuit.Fact{Predicate: biscuit.Predicate{Name: "arg", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(name), value}}}
+	}
+	argItem := func(name string, index int, subField string, value biscuit.Atom) biscuit.Fact {
+		return biscuit.Fact{Predicate: biscuit.Predicate{Name: "arg_item", IDs: []biscuit.Atom{
+			biscuit.Symbol("ambient"), biscuit.String(name), biscuit.Integer(index), biscuit.String(subField), value,
+		}}}
+	}
+
+	commonFacts := []biscuit.Fact{
+		arg("env", biscuit.String("V1")),
+		arg("password", biscuit.String("")),
+		arg("obj.name", biscuit.String("obj1")),
+		arg("obj.value", biscuit.Integer(1)),
+	}
+	setFacts := []biscuit.Fact{
+		arg("secrets.value", biscuit.Set{biscuit.String("secret1"), biscuit.String("secret2")}),
+	}
+	indexedFacts := []biscuit.Fact{
+		argItem("secrets", 0, "value", biscuit.String("secret1")),
+		argItem("secrets", 1, "value", biscuit.String("secret2")),
+	}
+
+	testCases := []struct {
+		format   RepeatedFormat
+		expected []biscuit.Fact
+	}{
+		{format: RepeatedSet, expected: append(append([]biscuit.Fact{}, commonFacts...), setFacts...)},
+		{format: RepeatedIndexed, expected: append(append([]biscuit.Fact{}, commonFacts...), indexedFacts...)},
+		{format: RepeatedSetAndIndexed, expected: append(append(append([]biscuit.Fact{}, commonFacts...), setFacts...), indexedFacts...)},
+	}
+	for _, testCase := range testCases {
+		c := newConverter(Options{RepeatedFormat: testCase.format})
+
+		facts, err := c.messageFacts(msg.ProtoReflect())
+		require.NoError(t, err)
+		require.ElementsMatch(t, testCase.expected, facts, "format %d", testCase.format)
+	}
+}
+
+func TestConverterPresenceFacts(t *testing.T) {
+	msg := prototesting.Presence{
+		Env:    prototesting.Enum_V1,
+		Object: &prototesting.Object{},
+		Target: &prototesting.Presence_Name{Name: "name1"},
+		Parent: &prototesting.Presence{
+			Target: &prototesting.Presence_Id{Id: 0},
+		},
+		Children: map[string]*prototesting.Presence{
+			"c1": {Object: &prototesting.Object{Name: "obj1"}},
+		},
+	}
+
+	has := func(name string) biscuit.Fact {
+		return biscuit.Fact{Predicate: biscuit.Predicate{Name: "has", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(name)}}}
+	}
+	oneof := func(name, oneofCase string) biscuit.Fact {
+		return biscuit.Fact{Predicate: biscuit.Predicate{Name: "oneof", IDs: []biscuit.Atom{biscuit.Symbol("ambient"), biscuit.String(name), biscuit.String(oneofCase)}}}
+	}
+
+	c := newConverter(Options{})
+	require.ElementsMatch(t, []biscuit.Fact{
+		has("object"),
+		has("name"),
+		oneof("target", "name"),
+		has("parent"),
+		has("parent.id"),
+		oneof("parent.target", "id"),
+		has("children.c1.object"),
+	}, c.presenceFacts(msg.ProtoReflect(), ""))
+}
+
+func TestConverterFlattenSkipDefaults(t *testing.T) {
+	msg := prototesting.Presence{
+		Env:    prototesting.Enum_V1,
+		Object: &prototesting.Object{Name: "obj1"},
+		Target: &prototesting.Presence_Id{Id: 0},
+	}
+
+	c := newConverter(Options{})
+	out, err := c.flattenFields(msg.ProtoReflect())
+	require.NoError(t, err)
+	require.Equal(t, biscuit.String("V1"), out["env"], "default values are flattened by default")
+
+	c.opts.SkipDefaults = true
+	out, err = c.flattenFields(msg.ProtoReflect())
+	require.NoError(t, err)
+	require.Equal(t, map[biscuit.String]biscuit.Atom{
+		"object.name": biscuit.String("obj1"),
+		"id":          biscuit.Integer(0),
+	}, out)
+}
+
+func TestLimitsCheckFacts(t *testing.T) {
+	fact := biscuit.Fact{Predicate: biscuit.Predicate{Name: "arg", IDs: []biscuit.Atom{
+		biscuit.Symbol("ambient"),
+		biscuit.String("names"),
+		biscuit.Set{biscuit.String("a"), biscuit.String("bb"), biscuit.String("ccc")},
+	}}}
+
+	require.NoError(t, Limits{}.checkFacts([]biscuit.Fact{fact}))
+	require.NoError(t, Limits{MaxFacts: 3, MaxAtomBytes: 5}.checkFacts([]biscuit.Fact{fact}))
+	require.Error(t, Limits{MaxFacts: 2}.checkFacts([]biscuit.Fact{fact}), "set values are counted as facts")
+	require.Error(t, Limits{MaxAtomBytes: 2}.checkFacts([]biscuit.Fact{fact}), "set values are bounded")
+}
+
+func TestFixedPoint(t *testing.T) {
+	testCases := []struct {
+		f        float64
+		bitSize  int
+		scale    int
+		expected int64
+		ok       bool
+	}{
+		{f: 12.345, scale: 2, expected: 1235, ok: true},
+		{f: 12.341, scale: 2, expected: 1235, ok: true},
+		{f: -12.345, scale: 2, expected: -1234, ok: true},
+		{f: -12.349, scale: 2, expected: -1234, ok: true},
+		{f: 12.345, scale: 0, expected: 13, ok: true},
+		{f: 12.345, scale: 3, expected: 12345, ok: true},
+		{f: 12.345, scale: 5, expected: 1234500, ok: true},
+		{f: 1.005, scale: 2, expected: 101, ok: true},
+		{f: 0.285, scale: 2, expected: 29, ok: true},
+		{f: 1.1, scale: 2, expected: 110, ok: true},
+		{f: float64(float32(1.1)), bitSize: 32, scale: 2, expected: 110, ok: true},
+		// upper bounds such as $0 <= 1000 hold only for values within them
+		{f: 10.004, scale: 2, expected: 1001, ok: true},
+		{f: 10, scale: 2, expected: 1000, ok: true},
+		{f: -0.001, scale: 2, expected: 0, ok: true},
+		{f: 1e-30, scale: 18, expected: 1, ok: true},
+		{f: 0.1, scale: 18, expected: 100000000000000000, ok: true},
+		{f: 1e19, scale: 0},
+		{f: -1e19, scale: 0},
+		{f: math.MaxInt64, scale: 0},
+		{f: math.NaN(), scale: 2},
+		{f: math.Inf(1), scale: 2},
+		{f: math.Inf(-1), scale: 2},
+	}
+
+	for _, testCase := range testCases {
+		if testCase.bitSize == 0 {
+			testCase.bitSize = 64
+		}
+		i, ok := fixedPoint(testCase.f, testCase.bitSize, testCase.scale)
+		require.Equal(t, testCase.ok, ok, "%v scale %d", testCase.f, testCase.scale)
+		require.Equal(t, testCase.expected, i, "%v scale %d", testCase.f, testCase.scale)
+	}
+}
+
+func benchmarkReadRequest() *pb.ReadRequest {
+	return &pb.ReadRequest{
+		Env:   pb.Env_STG,
+		Names: []string{"entity1", "entity2", "entity3"},
+		Stuff: map[string]*pb.Entity{
+			"a": {Name: "entity1", Value: 1},
+			"b": {Name: "entity2", Value: 2},
+		},
+		Stuff2:     map[int64]*pb.Entity{1: {Name: "entity1", Value: 1}},
+		Stuff3:     map[bool]*pb.Entity{true: {Name: "entity1", Value: 1}},
+		ExpireTime: timestamppb.New(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Entities: []*pb.Entity{
+			{Name: "entity1", Value: 1},
+			{Name: "entity2", Value: 2},
+			{Name: "entity3", Value: 3},
+		},
+	}
+}
+
+func BenchmarkConverterFlattenFields(b *testing.B) {
+	msg := benchmarkReadRequest().ProtoReflect()
+	c := newConverter(Options{})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.flattenFields(msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	msg := benchmarkReadRequest()
+	opts := Options{
+		RepeatedFormat: RepeatedSetAndIndexed,
+		PresenceFacts:  true,
+		Limits:         Limits{MaxDepth: 32, MaxFacts: 10000, MaxAtomBytes: 64 << 10},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Convert(msg, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	msg := prototesting.WithOptions{
+		Password: "secret",
+		Object:   &prototesting.Object{Name: "obj1"},
+	}
+
+	fact := func(name string, ids ...biscuit.Atom) biscuit.Fact {
+		return biscuit.Fact{Predicate: biscuit.Predicate{Name: name, IDs: append([]biscuit.Atom{biscuit.Symbol("ambient")}, ids...)}}
+	}
+
+	result, err := Convert(&msg, Options{
+		Names:         Names{Field: "resp", Has: "resp_has"},
+		PresenceFacts: true,
+		SkipDefaults:  true,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []biscuit.Fact{
+		fact("resp", biscuit.String("password"), biscuit.String("secret")),
+		fact("resp", biscuit.String("obj.name"), biscuit.String("obj1")),
+		fact("resp_has", biscuit.String("obj")),
+	}, result.Facts)
+	require.Equal(t, map[biscuit.String]struct{}{"password": {}}, result.Sensitive)
+
+	_, err = Convert(&msg, Options{FloatScale: MaxFloatScale + 1})
+	require.Error(t, err, "options are validated")
+}
+
+func TestConvertLimits(t *testing.T) {
+	msg := prototesting.Presence{
+		Parent: &prototesting.Presence{
+			Parent: &prototesting.Presence{Target: &prototesting.Presence_Id{Id: 1}},
+		},
+	}
+
+	_, err := Facts(&msg, Options{Limits: Limits{MaxDepth: 2}})
+	require.NoError(t, err)
+
+	_, err = Facts(&msg, Options{Limits: Limits{MaxDepth: 1}})
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitDepth, limitErr.Limit)
+	require.Equal(t, 1, limitErr.Max)
+
+	_, err = Facts(&msg, Options{Limits: Limits{MaxFacts: 1}})
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitFacts, limitErr.Limit)
+}
+
+func TestConvertLimitsNestedValues(t *testing.T) {
+	nested := map[string]interface{}{"leaf": "value"}
+	for i := 0; i < 50; i++ {
+		nested = map[string]interface{}{"a": nested}
+	}
+	deepStruct, err := structpb.NewStruct(nested)
+	require.NoError(t, err)
+	deepList := structpb.NewStringValue("value")
+	for i := 0; i < 50; i++ {
+		deepList = structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{deepList}})
+	}
+	deepAny := &prototesting.WellKnown{StringValue: wrapperspb.String("value")}
+	for i := 0; i < 50; i++ {
+		a, err := anypb.New(deepAny)
+		require.NoError(t, err)
+		deepAny = &prototesting.WellKnown{Any: a}
+	}
+
+	for _, msg := range []*prototesting.WellKnown{{Struct: deepStruct}, {Value: deepList}, deepAny} {
+		_, err := Facts(msg, Options{Limits: Limits{MaxDepth: 2}})
+		var limitErr *LimitError
+		require.True(t, errors.As(err, &limitErr), "nested values count in the depth of %v", msg)
+		require.Equal(t, LimitDepth, limitErr.Limit)
+
+		_, err = Facts(msg, Options{Limits: Limits{MaxDepth: 128}})
+		require.NoError(t, err)
+	}
+}
+
+func TestConvertLimitsFactsCount(t *testing.T) {
+	msg := &prototesting.Dummy{
+		RepeatedStr:     []string{"a", "b", "c"},
+		RepeatedObjects: []*prototesting.Object{{Name: "obj1", Value: 1}, {Name: "obj2", Value: 2}},
+		SingleObject:    &prototesting.Object{Name: "single", Value: 3},
+	}
+
+	for _, format := range []RepeatedFormat{RepeatedSet, RepeatedIndexed, RepeatedSetAndIndexed} {
+		opts := Options{RepeatedFormat: format, SkipDefaults: true}
+		facts, err := Facts(msg, opts)
+		require.NoError(t, err)
+		count := 0
+		for _, fact := range facts {
+			count++
+			if set, ok := fact.IDs[2].(biscuit.Set); ok {
+				count += len(set) - 1
+			}
+		}
+
+		opts.Limits.MaxFacts = count
+		_, err = Facts(msg, opts)
+		require.NoError(t, err, "format %d holds %d facts", format, count)
+
+		opts.Limits.MaxFacts = count - 1
+		_, err = Facts(msg, opts)
+		var limitErr *LimitError
+		require.True(t, errors.As(err, &limitErr), "format %d", format)
+		require.Equal(t, LimitFacts, limitErr.Limit)
+	}
+
+	// facts are counted while flattening, before the whole message is
+	c := newConverter(Options{RepeatedFormat: RepeatedIndexed, Limits: Limits{MaxFacts: 2}})
+	_, err := c.flattenFields(msg.ProtoReflect())
+	require.Error(t, err)
+	c = newConverter(Options{RepeatedFormat: RepeatedIndexed, Limits: Limits{MaxFacts: 2}})
+	_, err = c.flattenItems(msg.ProtoReflect(), "", nil)
+	require.Error(t, err, "items are counted")
+
+	// the items and the fields are counted together, even when each of them is within the limit
+	objects := make([]*prototesting.Object, 10)
+	for i := range objects {
+		objects[i] = &prototesting.Object{Name: fmt.Sprintf("obj%d", i), Value: int64(i)}
+	}
+	large := &prototesting.Dummy{RepeatedObjects: objects}
+	opts := Options{RepeatedFormat: RepeatedSetAndIndexed, SkipDefaults: true, Limits: Limits{MaxFacts: 25}}
+	c = newConverter(opts)
+	_, err = c.flattenFields(large.ProtoReflect())
+	require.NoError(t, err, "the 20 field values are within the limit")
+	c = newConverter(opts)
+	_, err = c.flattenItems(large.ProtoReflect(), "", nil)
+	require.NoError(t, err, "the 20 items are within the limit")
+	_, err = Facts(large, opts)
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr), "the 40 field values and items exceed the limit")
+	require.Equal(t, LimitFacts, limitErr.Limit)
+}
@@ -0,0 +1,171 @@
+package protofacts
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Redact clears the fields of msg at path, named as their facts, i.e., "entities.value" clears the value of every
+// element of the repeated entities field. Fields are named after their aliases, map entries and Struct keys are
+// deleted, and paths into an Any are resolved against the type of its message, which is re-encoded once redacted.
+// It returns false when path names no field of msg, such as a field skipped by its authz options or a sub field
+// of a scalar, so callers can reject unknown paths rather than leaving the field they meant untouched. Paths into
+// map entries, Struct keys and Any values match whether they are set or not.
+func Redact(msg proto.Message, path string) (bool, error) {
+	return redactMessage(msg.ProtoReflect(), path)
+}
+
+// redactMessage clears the fields of msg at path, and returns whether path names a field of msg.
+func redactMessage(msg protoreflect.Message, path string) (bool, error) {
+	desc := msg.Descriptor()
+	switch desc.FullName() {
+	case "google.protobuf.Struct":
+		redactStruct(msg.Interface().(*structpb.Struct), path)
+		return true, nil
+	case "google.protobuf.Value":
+		redactStructValue(msg.Interface().(*structpb.Value), path)
+		return true, nil
+	case "google.protobuf.ListValue":
+		for _, value := range msg.Interface().(*structpb.ListValue).GetValues() {
+			redactStructValue(value, path)
+		}
+		return true, nil
+	case "google.protobuf.Any":
+		return redactAny(msg.Interface().(*anypb.Any), path)
+	}
+	if isWellKnownType(desc) {
+		// other well known types are single values, without sub fields
+		return false, nil
+	}
+
+	compiled := compiledMessageOf(desc)
+	if compiled.skip {
+		return false, nil
+	}
+
+	var matched bool
+	for i := range compiled.fields {
+		field := &compiled.fields[i]
+		if path == field.name {
+			msg.Clear(field.desc)
+			matched = true
+			continue
+		}
+		if !strings.HasPrefix(path, field.name+".") || field.value.Kind() != protoreflect.MessageKind && !field.desc.IsMap() {
+			continue
+		}
+
+		fieldMatched, err := redactField(msg, field, strings.TrimPrefix(path, field.name+"."))
+		if err != nil {
+			return false, err
+		}
+		matched = matched || fieldMatched
+	}
+
+	return matched, nil
+}
+
+// redactField clears the sub fields at path of the values of field in msg, deleting the map entries named by path.
+// Unset message fields and empty repeated fields are matched against an empty message of their type.
+func redactField(msg protoreflect.Message, field *compiledField, path string) (bool, error) {
+	switch {
+	case field.desc.IsMap():
+		if !msg.Has(field.desc) {
+			return true, nil
+		}
+		m := msg.Mutable(field.desc).Map()
+		matched := true
+		var deleted []protoreflect.MapKey
+		var err error
+		m.Range(func(mk protoreflect.MapKey, value protoreflect.Value) bool {
+			key := mk.String()
+			switch {
+			case path == key:
+				deleted = append(deleted, mk)
+			case field.value.Kind() == protoreflect.MessageKind && strings.HasPrefix(path, key+"."):
+				matched, err = redactMessage(value.Message(), strings.TrimPrefix(path, key+"."))
+			}
+			return err == nil && matched
+		})
+		for _, mk := range deleted {
+			m.Clear(mk)
+		}
+		return matched, err
+	case field.desc.IsList():
+		if !msg.Has(field.desc) {
+			return redactMessage(msg.NewField(field.desc).List().NewElement().Message(), path)
+		}
+		list := msg.Mutable(field.desc).List()
+		var matched bool
+		for j := 0; j < list.Len(); j++ {
+			eltMatched, err := redactMessage(list.Get(j).Message(), path)
+			if err != nil {
+				return false, err
+			}
+			matched = matched || eltMatched
+		}
+		return matched, nil
+	case !msg.Has(field.desc):
+		return redactMessage(msg.NewField(field.desc).Message(), path)
+	default:
+		return redactMessage(msg.Mutable(field.desc).Message(), path)
+	}
+}
+
+// redactAny clears the fields at path of the message held by a, resolved from the global registry, and encodes it
+// back into a. Unset values match any path.
+func redactAny(a *anypb.Any, path string) (bool, error) {
+	if a.GetTypeUrl() == "" {
+		return true, nil
+	}
+
+	msgType, err := protoregistry.GlobalTypes.FindMessageByURL(a.TypeUrl)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve any type %q: %w", a.TypeUrl, err)
+	}
+	resolved := msgType.New().Interface()
+	if err := proto.Unmarshal(a.Value, resolved); err != nil {
+		return false, fmt.Errorf("failed to unmarshal any type %q: %w", a.TypeUrl, err)
+	}
+
+	matched, err := redactMessage(resolved.ProtoReflect(), path)
+	if err != nil || !matched {
+		return matched, err
+	}
+	value, err := proto.Marshal(resolved)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal any type %q: %w", a.TypeUrl, err)
+	}
+	a.Value = value
+	return true, nil
+}
+
+// redactStruct deletes the keys of s at path, named as insertStructValue names them.
+func redactStruct(s *structpb.Struct, path string) {
+	for k, value := range s.GetFields() {
+		switch {
+		case path == k:
+			delete(s.Fields, k)
+		case strings.HasPrefix(path, k+"."):
+			redactStructValue(value, strings.TrimPrefix(path, k+"."))
+		}
+	}
+}
+
+// redactStructValue deletes the keys at path of the objects held by value, directly or in its lists.
+func redactStructValue(value *structpb.Value, path string) {
+	switch kind := value.GetKind().(type) {
+	case *structpb.Value_StructValue:
+		redactStruct(kind.StructValue, path)
+	case *structpb.Value_ListValue:
+		for _, listValue := range kind.ListValue.GetValues() {
+			redactStructValue(listValue, path)
+		}
+	}
+}
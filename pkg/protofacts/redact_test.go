@@ -0,0 +1,241 @@
+package protofacts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	prototesting "demo/pkg/protofacts/testing"
+)
+
+func TestRedact(t *testing.T) {
+	newAny := func(t *testing.T, msg proto.Message) *anypb.Any {
+		a, err := anypb.New(msg)
+		require.NoError(t, err)
+		return a
+	}
+	newStruct := func(t *testing.T, v map[string]interface{}) *structpb.Struct {
+		s, err := structpb.NewStruct(v)
+		require.NoError(t, err)
+		return s
+	}
+
+	testCases := []struct {
+		name     string
+		msg      func(t *testing.T) proto.Message
+		path     string
+		expected func(t *testing.T, m proto.Message)
+		matched  bool
+	}{
+		{
+			name:     "enum",
+			path:     "enum",
+			expected: func(t *testing.T, m proto.Message) { m.(*prototesting.Dummy).Enum = prototesting.Enum_V1 },
+			matched:  true,
+		},
+		{
+			name:     "repeated scalars",
+			path:     "repeated_str",
+			expected: func(t *testing.T, m proto.Message) { m.(*prototesting.Dummy).RepeatedStr = nil },
+			matched:  true,
+		},
+		{
+			name:     "well known type",
+			path:     "timestamp",
+			expected: func(t *testing.T, m proto.Message) { m.(*prototesting.Dummy).Timestamp = nil },
+			matched:  true,
+		},
+		{
+			name:     "message sub field",
+			path:     "single_object.value",
+			expected: func(t *testing.T, m proto.Message) { m.(*prototesting.Dummy).SingleObject.Value = 0 },
+			matched:  true,
+		},
+		{
+			name: "repeated message sub field",
+			path: "repeated_objects.value",
+			expected: func(t *testing.T, m proto.Message) {
+				m.(*prototesting.Dummy).RepeatedObjects[0].Value = 0
+				m.(*prototesting.Dummy).RepeatedObjects[1].Value = 0
+			},
+			matched: true,
+		},
+		{
+			name:     "map entry sub field",
+			path:     "map_str_object.a.name",
+			expected: func(t *testing.T, m proto.Message) { m.(*prototesting.Dummy).MapStrObject["a"].Name = "" },
+			matched:  true,
+		},
+		{
+			name:     "map entry",
+			path:     "map_str_object.b",
+			expected: func(t *testing.T, m proto.Message) { delete(m.(*prototesting.Dummy).MapStrObject, "b") },
+			matched:  true,
+		},
+		{
+			name:     "map int entry sub field",
+			path:     "map_int_object.41.value",
+			expected: func(t *testing.T, m proto.Message) { m.(*prototesting.Dummy).MapIntObject[41].Value = 0 },
+			matched:  true,
+		},
+		{
+			name:     "missing map entry",
+			path:     "map_str_object.c",
+			expected: func(t *testing.T, m proto.Message) {},
+			matched:  true,
+		},
+		{
+			name:     "unset message sub field",
+			msg:      func(t *testing.T) proto.Message { return &prototesting.Dummy{} },
+			path:     "single_object.value",
+			expected: func(t *testing.T, m proto.Message) {},
+			matched:  true,
+		},
+		{
+			name:     "empty repeated message sub field",
+			msg:      func(t *testing.T) proto.Message { return &prototesting.Dummy{} },
+			path:     "repeated_objects.value",
+			expected: func(t *testing.T, m proto.Message) {},
+			matched:  true,
+		},
+		{
+			name:     "unknown field",
+			path:     "unknown.field",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name:     "unknown sub field",
+			path:     "single_object.unknown",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name:     "empty repeated message unknown sub field",
+			msg:      func(t *testing.T) proto.Message { return &prototesting.Dummy{} },
+			path:     "repeated_objects.unknown",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name:     "scalar sub field",
+			path:     "enum.sub",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name:     "well known type sub field",
+			path:     "timestamp.seconds",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name: "alias",
+			msg: func(t *testing.T) proto.Message {
+				return &prototesting.WithOptions{Environment: prototesting.Enum_V2, Object: &prototesting.Object{Name: "obj1", Value: 1}}
+			},
+			path:     "obj.name",
+			expected: func(t *testing.T, m proto.Message) { m.(*prototesting.WithOptions).Object.Name = "" },
+			matched:  true,
+		},
+		{
+			name: "field name of an alias",
+			msg: func(t *testing.T) proto.Message {
+				return &prototesting.WithOptions{Environment: prototesting.Enum_V2}
+			},
+			path:     "environment",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name:     "skipped field",
+			msg:      func(t *testing.T) proto.Message { return &prototesting.WithOptions{Blob: []byte("blob")} },
+			path:     "blob",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name: "skipped message",
+			msg: func(t *testing.T) proto.Message {
+				return &prototesting.WithOptions{Skipped: &prototesting.Skipped{Value: "value"}}
+			},
+			path:     "skipped.value",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+		{
+			name: "struct key",
+			msg: func(t *testing.T) proto.Message {
+				return &prototesting.WellKnown{Struct: newStruct(t, map[string]interface{}{
+					"a": map[string]interface{}{"b": "secret", "c": "public"},
+					"d": []interface{}{map[string]interface{}{"b": "secret"}},
+				})}
+			},
+			path: "struct.a.b",
+			expected: func(t *testing.T, m proto.Message) {
+				m.(*prototesting.WellKnown).Struct = newStruct(t, map[string]interface{}{
+					"a": map[string]interface{}{"c": "public"},
+					"d": []interface{}{map[string]interface{}{"b": "secret"}},
+				})
+			},
+			matched: true,
+		},
+		{
+			name: "struct list key",
+			msg: func(t *testing.T) proto.Message {
+				return &prototesting.WellKnown{Struct: newStruct(t, map[string]interface{}{
+					"d": []interface{}{map[string]interface{}{"b": "secret", "c": "public"}},
+				})}
+			},
+			path: "struct.d.b",
+			expected: func(t *testing.T, m proto.Message) {
+				m.(*prototesting.WellKnown).Struct = newStruct(t, map[string]interface{}{
+					"d": []interface{}{map[string]interface{}{"c": "public"}},
+				})
+			},
+			matched: true,
+		},
+		{
+			name: "any sub field",
+			msg: func(t *testing.T) proto.Message {
+				return &prototesting.WellKnown{Any: newAny(t, &prototesting.Object{Name: "obj1", Value: 1})}
+			},
+			path: "any.value",
+			expected: func(t *testing.T, m proto.Message) {
+				m.(*prototesting.WellKnown).Any = newAny(t, &prototesting.Object{Name: "obj1"})
+			},
+			matched: true,
+		},
+		{
+			name: "any unknown sub field",
+			msg: func(t *testing.T) proto.Message {
+				return &prototesting.WellKnown{Any: newAny(t, &prototesting.Object{Name: "obj1", Value: 1})}
+			},
+			path:     "any.unknown",
+			expected: func(t *testing.T, m proto.Message) {},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var msg proto.Message = &prototesting.Dummy{
+				Enum:            prototesting.Enum_V2,
+				RepeatedStr:     []string{"a", "b"},
+				MapStrObject:    map[string]*prototesting.Object{"a": {Name: "str1", Value: 1}, "b": {Name: "str2", Value: 2}},
+				MapIntObject:    map[int64]*prototesting.Object{41: {Name: "int1", Value: 1}},
+				Timestamp:       timestamppb.Now(),
+				RepeatedObjects: []*prototesting.Object{{Name: "obj1", Value: 11}, {Name: "obj2", Value: 12}},
+				SingleObject:    &prototesting.Object{Name: "single1", Value: 12},
+			}
+			if testCase.msg != nil {
+				msg = testCase.msg(t)
+			}
+			expected := proto.Clone(msg)
+			testCase.expected(t, expected)
+
+			matched, err := Redact(msg, testCase.path)
+			require.NoError(t, err)
+			require.Equal(t, testCase.matched, matched)
+			require.True(t, proto.Equal(expected, msg), "expected %v, got %v", expected, msg)
+		})
+	}
+
+	_, err := Redact(&prototesting.WellKnown{Any: &anypb.Any{TypeUrl: "type.googleapis.com/unknown.Type"}}, "any.value")
+	require.Error(t, err)
+}